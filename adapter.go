@@ -1,16 +1,50 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ccmonky/log"
 )
 
 // Adapt defaultAdapter's Adapt
 func Adapt(err error, guard MetaError) error {
-	return defaultAdapter.Adapt(err, guard)
+	defaultAdapterLock.RLock()
+	a := defaultAdapter
+	defaultAdapterLock.RUnlock()
+	return a.Adapt(err, guard)
+}
+
+// AdaptCtx defaultAdapter's AdaptCtx if it implements `ContextAdapter`, otherwise apply options carried by ctx and then
+// call defaultAdapter's Adapt
+func AdaptCtx(ctx context.Context, err error, guard MetaError) error {
+	defaultAdapterLock.RLock()
+	a := defaultAdapter
+	defaultAdapterLock.RUnlock()
+	if ca, ok := a.(ContextAdapter); ok {
+		return ca.AdaptCtx(ctx, err, guard)
+	}
+	return a.Adapt(FromContext(ctx, err), guard)
+}
+
+// NewDefaultAdapter creates a new Adapter with the same options as the default one, and then apply opts, usually used
+// with `SetDefaultAdapter` to customize the default adapter
+func NewDefaultAdapter(opts ...AdapterOption) Adapter {
+	return NewAdapter(append(defaultAdapterOptions[:len(defaultAdapterOptions):len(defaultAdapterOptions)], opts...)...)
+}
+
+// SetDefaultAdapter replace the default adapter used by `Adapt`, nil will be ignored
+func SetDefaultAdapter(a Adapter) {
+	if a == nil {
+		return
+	}
+	defaultAdapterLock.Lock()
+	defer defaultAdapterLock.Unlock()
+	defaultAdapter = a
 }
 
 // Adapter provides `Adapt` mainly to support suggestive error, error delivery path, error overrides ...
@@ -18,12 +52,24 @@ type Adapter interface {
 	Adapt(err error, guard MetaError) error
 }
 
+// ContextAdapter is an `Adapter` which can also adapt with a context.Context
+type ContextAdapter interface {
+	Adapter
+	AdaptCtx(ctx context.Context, err error, guard MetaError) error
+}
+
 // NewAdapter creates a new Adapter, usually no need to create a new one, just use the default `Adapt` function is enough
 func NewAdapter(opts ...AdapterOption) Adapter {
 	a := adapter{}
 	for _, opt := range opts {
 		opt(&a)
 	}
+	if a.CallerFunc == nil {
+		a.CallerFunc = caller
+	}
+	if a.MetaMappingFunc == nil {
+		a.MetaMappingFunc = mappingBySourceCode
+	}
 	return &a
 }
 
@@ -65,12 +111,38 @@ func WithMetaMappingFunc(fn func(*Meta) MetaError) AdapterOption {
 	}
 }
 
+// WithReplaceDeprecated rewrite deprecated MetaErrors(both the guard and the latest one in err) to their replacements
+func WithReplaceDeprecated() AdapterOption {
+	return func(a *adapter) {
+		a.ReplaceDeprecated = true
+	}
+}
+
+// WithContextErrAware specify whether to attach `Canceled` or `DeadlineExceeded` as the latest meta if the context
+// (passed to `AdaptCtx` or carried by `CtxAttr`) is done when adapt, default to true
+func WithContextErrAware(aware bool) AdapterOption {
+	return func(a *adapter) {
+		a.IgnoreContextErr = !aware
+	}
+}
+
+// WithImport import the structure of errors created by other error libraries(see `Import`) before adapt, it's off by
+// default since imported attrs change the output of `Error()`, e.g. `errors.SetDefaultAdapter(errors.NewDefaultAdapter(errors.WithImport()))`
+func WithImport() AdapterOption {
+	return func(a *adapter) {
+		a.Import = true
+	}
+}
+
 type adapter struct {
-	AddCaller       bool
-	CallerSkip      int
-	CallerFunc      func(skip int) string
-	DefaultOptions  []Option
-	MetaMappingFunc func(*Meta) MetaError
+	Import            bool
+	AddCaller         bool
+	CallerSkip        int
+	CallerFunc        func(skip int) string
+	DefaultOptions    []Option
+	MetaMappingFunc   func(*Meta) MetaError
+	ReplaceDeprecated bool
+	IgnoreContextErr  bool
 }
 
 // Adapt append guard into err if err is not MetaError, otherwise only apply adapter's caller & default options,
@@ -78,36 +150,83 @@ type adapter struct {
 // that is, if dyn's app != current app name, then it will be considered as an Meta casted from upstream, so it can not be
 // used directly, and should be mapping to current app's meta, the default mapping is by source+code, unless you specify
 // a mapping funciton
+//
+// NOTE: if err carries context by `CtxAttr`, the options carried by the context(see `WithContextAttrs`) will be applied
 func (a *adapter) Adapt(err error, fallback MetaError) error {
 	if err == nil {
 		return nil
 	}
+	var callerName string
+	if a.AddCaller {
+		callerName = a.CallerFunc(a.CallerSkip)
+	}
+	return a.adapt(CtxAttr.Get(err), err, fallback, callerName)
+}
+
+// AdaptCtx is the same as `Adapt`, but apply the options carried by ctx(see `WithContextAttrs`)
+func (a *adapter) AdaptCtx(ctx context.Context, err error, fallback MetaError) error {
+	if err == nil {
+		return nil
+	}
+	var callerName string
+	if a.AddCaller {
+		callerName = a.CallerFunc(a.CallerSkip)
+	}
+	return a.adapt(ctx, err, fallback, callerName)
+}
+
+func (a *adapter) adapt(ctx context.Context, err error, fallback MetaError, callerName string) error {
 	if fallback == nil {
 		fallback = Unknown
 	}
+	if a.Import {
+		err = Import(err)
+	}
 	if fallback.App() != AppName() {
 		log.Panicf("gurad meta error's app(%s) != current app name(%s)\n", fallback.App(), AppName())
 		return err
 	}
+	if a.ReplaceDeprecated {
+		fallback = ResolveDeprecated(fallback)
+		if latest := GetLatestMetaError(err); IsDeprecated(latest) {
+			if replacement := ResolveDeprecated(latest); replacement != latest {
+				err = attachError(err, replacement)
+			}
+		}
+	}
+	if !a.IgnoreContextErr && ctx != nil && ctx.Err() != nil {
+		// NOTE: errors created under a done context are usually caused by the cancellation
+		target := Canceled
+		if stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+			target = DeadlineExceeded
+		}
+		if GetLatestMetaError(err) != target {
+			err = attachError(err, target)
+		}
+	}
 	var opts []Option
 	if a.AddCaller {
-		callerName := a.CallerFunc(a.CallerSkip)
 		opts = append(opts, CallerAttr.Option(callerName))
 	}
 	opts = append(opts, a.DefaultOptions...)
+	if ctx != nil {
+		opts = append(opts, func(err error) error {
+			return FromContext(ctx, err)
+		})
+	}
 	dyn := MetaAttr.Get(err)
 	if dyn == nil {
-		return With(WithError(err, fallback), opts...)
+		return observe(ObserveAdapt, With(attachError(err, fallback), opts...))
 	}
 	if dyn.App() != AppName() { // NOTE: maybe upstream case || bad dynamic case
 		e := a.MetaMappingFunc(dyn) // NOTE: try to map to current app's meta error by source & code
 		if e != nil {
-			return With(WithError(err, fallback), opts...)
+			return observe(ObserveAdapt, With(attachError(err, fallback), opts...))
 		}
 		log.Panicf("can not found current app's meta error for %s:%s\n", err, dyn.Source(), dyn.Code())
 		return err
 	}
-	return With(err, opts...)
+	return observe(ObserveAdapt, With(err, opts...))
 }
 
 func caller(skip int) string {
@@ -129,12 +248,18 @@ func mappingBySourceCode(upstream *Meta) MetaError {
 	return nil
 }
 
-var defaultAdapter = NewAdapter(
-	WithAddCaller(),
-	WithCallerSkip(3),
-	WithCallerFunc(caller),
-	WithMetaMappingFunc(mappingBySourceCode))
+var (
+	defaultAdapterOptions = []AdapterOption{
+		WithAddCaller(),
+		WithCallerSkip(3),
+		WithCallerFunc(caller),
+		WithMetaMappingFunc(mappingBySourceCode),
+	}
+	defaultAdapter     = NewAdapter(defaultAdapterOptions...)
+	defaultAdapterLock sync.RWMutex
+)
 
 var (
-	_ Adapter = (*adapter)(nil)
+	_ Adapter        = (*adapter)(nil)
+	_ ContextAdapter = (*adapter)(nil)
 )
@@ -0,0 +1,48 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptContextErr(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	err := errors.AdaptCtx(ctx, io.ErrUnexpectedEOF, errors.Unavailable)
+	assert.Equalf(t, errors.Unavailable, errors.GetLatestMetaError(err), "not done")
+
+	cancel()
+	err = errors.AdaptCtx(ctx, io.ErrUnexpectedEOF, errors.Unavailable)
+	assert.Equalf(t, errors.Canceled, errors.GetLatestMetaError(err), "canceled")
+	assert.Equalf(t, io.ErrUnexpectedEOF, errors.Cause(err), "cause kept")
+	assert.Equalf(t, 499, errors.StatusAttr.Get(err), "canceled status")
+
+	err = errors.AdaptCtx(ctx, errors.WithError(io.ErrUnexpectedEOF, errors.Unavailable), errors.Unknown)
+	assert.Equalf(t, errors.Canceled, errors.GetLatestMetaError(err), "canceled override meta")
+	assert.Truef(t, errors.Is(err, errors.Unavailable), "still unavailable")
+	err = errors.AdaptCtx(ctx, err, errors.Unknown)
+	assert.Equalf(t, []error{errors.Unavailable, errors.Canceled}, errors.ErrorAttr.GetAll(err), "not attach twice")
+
+	err = errors.Adapt(errors.WithCtx(io.ErrUnexpectedEOF, ctx), errors.Unavailable)
+	assert.Equalf(t, errors.Canceled, errors.GetLatestMetaError(err), "canceled by ctx attr")
+
+	dctx, dcancel := context.WithDeadline(context.TODO(), time.Now().Add(-time.Second))
+	defer dcancel()
+	err = errors.AdaptCtx(dctx, io.ErrUnexpectedEOF, errors.Unavailable)
+	assert.Equalf(t, errors.DeadlineExceeded, errors.GetLatestMetaError(err), "deadline exceeded")
+
+	err = errors.NewDefaultAdapter(errors.WithContextErrAware(false)).(errors.ContextAdapter).AdaptCtx(ctx, io.ErrUnexpectedEOF, errors.Unavailable)
+	assert.Equalf(t, errors.Unavailable, errors.GetLatestMetaError(err), "disabled")
+}
+
+func TestNewDefaultAdapter(t *testing.T) {
+	errors.SetDefaultAdapter(errors.NewDefaultAdapter(errors.WithDefaultOptions(errors.StatusOption(418))))
+	defer errors.SetDefaultAdapter(errors.NewDefaultAdapter())
+	err := errors.Adapt(errors.New("xxx"), errors.Unknown)
+	assert.Equalf(t, 418, errors.StatusAttr.Get(err), "default options")
+	assert.Equalf(t, "errors_test.TestNewDefaultAdapter:45", errors.CallerAttr.Get(err), "caller")
+}
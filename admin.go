@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewAdminHandler creates an `http.Handler` which serves error definitions and recent occurrences, recent can be nil:
+//
+//     /          html page of all below
+//     /catalog   registered meta errors(see `NewCatalog`) as json
+//     /attrs     registered attrs(see `AllAttrs`) as json
+//     /recent    recent occurrences as json, query: code, meta_id, since, until(RFC3339 or duration before now), limit
+//
+// Usage:
+//
+//     http.Handle("/debug/errors/", http.StripPrefix("/debug/errors", errors.NewAdminHandler(recent)))
+func NewAdminHandler(recent *RecentErrors) http.Handler {
+	return &adminHandler{recent: recent}
+}
+
+type adminHandler struct {
+	recent *RecentErrors
+}
+
+// ServeHTTP implement `http.Handler`
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "":
+		h.serveHTML(w, r)
+	case "/catalog":
+		writeAdminJSON(w, NewCatalog(""))
+	case "/attrs":
+		writeAdminJSON(w, AllAttrs())
+	case "/recent":
+		q, err := parseRecentQuery(r)
+		if err != nil {
+			writeHTTP(w, err)
+			return
+		}
+		writeAdminJSON(w, h.query(q))
+	default: // NOTE: failures of admin are not observed, so that they are not recorded into the errors it serves
+		writeHTTP(w, attachError(fmt.Errorf("admin path %s not found", r.URL.Path), NotFound))
+	}
+}
+
+func (h *adminHandler) query(q RecentQuery) []Occurrence {
+	if h.recent == nil {
+		return []Occurrence{}
+	}
+	if occurrences := h.recent.Query(q); occurrences != nil {
+		return occurrences
+	}
+	return []Occurrence{}
+}
+
+func (h *adminHandler) serveHTML(w http.ResponseWriter, r *http.Request) {
+	q, err := parseRecentQuery(r)
+	if err != nil {
+		writeHTTP(w, err)
+		return
+	}
+	attrs := make([]AttrInterface, 0)
+	for _, a := range AllAttrs() {
+		if ai, ok := a.(AttrInterface); ok {
+			attrs = append(attrs, ai)
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Name() < attrs[j].Name()
+	})
+	var buf bytes.Buffer // NOTE: render into buffer first, so that the error can be responded if failed
+	err = adminTemplate.Execute(&buf, map[string]any{
+		"Catalog": NewCatalog(""),
+		"Attrs":   attrs,
+		"Recent":  h.query(q),
+	})
+	if err != nil {
+		writeHTTP(w, attachError(fmt.Errorf("render admin page failed: %v", err), Internal))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+func parseRecentQuery(r *http.Request) (RecentQuery, error) {
+	values := r.URL.Query()
+	q := RecentQuery{
+		Code:   values.Get("code"),
+		MetaID: values.Get("meta_id"),
+	}
+	var err error
+	if q.Since, err = parseAdminTime(values.Get("since")); err != nil {
+		return q, attachError(fmt.Errorf("invalid since: %v", err), InvalidArgument)
+	}
+	if q.Until, err = parseAdminTime(values.Get("until")); err != nil {
+		return q, attachError(fmt.Errorf("invalid until: %v", err), InvalidArgument)
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil {
+			return q, attachError(fmt.Errorf("invalid limit: %v", err), InvalidArgument)
+		}
+	}
+	return q, nil
+}
+
+// parseAdminTime parse RFC3339 time or duration before now, e.g. `5m`
+func parseAdminTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		writeHTTP(w, attachError(fmt.Errorf("encode %T failed: %v", v, err), Internal))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+var adminTemplate = template.Must(template.New("admin").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>errors</title></head>
+<body>
+<h2>Recent</h2>
+<table border="1">
+<tr><th>time</th><th>point</th><th>meta</th><th>status</th><th>message</th><th>fingerprint</th></tr>
+{{range .Recent}}<tr><td>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</td><td>{{.Point}}</td><td>{{.MetaID}}</td><td>{{.Status}}</td><td>{{.Message}}</td><td>{{.Fingerprint}}</td></tr>
+{{end}}</table>
+<h2>Catalog</h2>
+<table border="1">
+<tr><th>id</th><th>status</th><th>message</th><th>deprecated</th></tr>
+{{range .Catalog.Entries}}<tr><td>{{.ID}}</td><td>{{.Status}}</td><td>{{.Message}}</td><td>{{.Deprecated}}</td></tr>
+{{end}}</table>
+<h2>Attrs</h2>
+<table border="1">
+<tr><th>name</th><th>description</th></tr>
+{{range .Attrs}}<tr><td>{{.Name}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var (
+	_ http.Handler = (*adminHandler)(nil)
+)
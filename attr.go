@@ -300,23 +300,16 @@ func NewAttrs(attrs ...AttrInterface) Attrs {
 
 // Map returns the name:value map of Attrs according to Attrs's order
 func (as Attrs) Map(err error) map[string]any {
-	var m = make(map[string]any, len(as)+5) // NOTE: 5 means flatten meta(4)+status(1) in most common scenarios
-	for _, a := range as {
-		v := Get(err, a.Key())
-		m[*a.Key().(*string)] = v
-		switch me := v.(type) {
-		case *Meta:
-			m[MetaAttrAppFieldName] = me.app()
-			m[MetaAttrSourceFieldName] = me.source
-			m[MetaAttrCodeFieldName] = me.code
-			m[MetaAttrMessageFieldName] = me.msg
-		case error:
-			mm := Map(me)
-			for kk, vv := range mm {
-				m[kk] = vv
-			}
+	var kvs []kv // NOTE: the last attr is the latest one, see `mergeMap`
+	for i := len(as) - 1; i >= 0; i-- {
+		v := Get(err, as[i].Key())
+		kvs = append(kvs, kv{*as[i].Key().(*string), v, 0})
+		if isChain(v) {
+			kvs = appendKVs(kvs, v.(error), 1)
 		}
 	}
+	var m = make(map[string]any, len(kvs)+5) // NOTE: 5 means flatten meta(4)+status(1) in most common scenarios
+	mergeMap(m, kvs)
 	return m
 }
 
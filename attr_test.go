@@ -78,6 +78,19 @@ func TestAttrs(t *testing.T) {
 	assert.Equalf(t, "source=errors;code=already_exists(6)", fmt.Sprint(m["meta"]), "meta")
 }
 
+func TestAttrsMapConsistent(t *testing.T) {
+	err := errors.WithError(errors.New("xxx"), errors.WithError(errors.NotFound, errors.Unavailable))
+	err = errors.WithMessage(err, "wrapper")
+	full := errors.Map(err)
+	m := errors.NewAttrs(errors.MessageAttr, errors.ErrorAttr).Map(err)
+	for _, name := range []string{"msg", "meta", "meta.code", "meta.app", "status", "error"} {
+		assert.Equalf(t, full[name], m[name], "same as Map: %s", name)
+	}
+	assert.Equalf(t, "unavailable(14)", m["meta.code"], "the latest meta of the nested chain wins")
+	m = errors.NewAttrs(errors.ErrorAttr, errors.StatusAttr).Map(errors.StatusAttr.With(err, 499))
+	assert.Equalf(t, 499, m["status"], "the last attr wins")
+}
+
 func BenchmarkNewAttrStrKey(b *testing.B) {
 	b.ReportAllocs()
 	meta := errors.NotFound
@@ -0,0 +1,195 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CatalogFormatVersion is the format version of `Catalog` snapshot
+const CatalogFormatVersion = 1
+
+// Catalog is a versioned snapshot of all registered MetaErrors, usually dumped on each release and compared by
+// `DiffCatalog` in CI to detect breaking changes
+type Catalog struct {
+	// Format is the snapshot format version, see `CatalogFormatVersion`
+	Format int `json:"format"`
+
+	// Version is the release version of the app when snapshot
+	Version string `json:"version"`
+
+	// Entries all MetaErrors sorted by id
+	Entries []CatalogEntry `json:"entries"`
+}
+
+// CatalogEntry is the snapshot of a single MetaError
+type CatalogEntry struct {
+	ID          string `json:"id"`
+	App         string `json:"app"`
+	Source      string `json:"source"`
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Status      int    `json:"status"`
+	Deprecated  string `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// NewCatalog creates a Catalog snapshot of all registered MetaErrors(see `AllMetaErrors`) with release version
+func NewCatalog(version string) *Catalog {
+	c := Catalog{
+		Format:  CatalogFormatVersion,
+		Version: version,
+	}
+	for id, me := range AllMetaErrors() {
+		entry := CatalogEntry{
+			ID:      id,
+			App:     me.App(),
+			Source:  me.Source(),
+			Code:    me.Code(),
+			Message: me.Message(),
+			Status:  StatusAttr.Get(me),
+		}
+		if d := DeprecatedAttr.Get(me); d != nil {
+			entry.Deprecated = d.Reason
+			entry.Replacement = d.replacementID()
+		}
+		c.Entries = append(c.Entries, entry)
+	}
+	sort.Slice(c.Entries, func(i, j int) bool {
+		return c.Entries[i].ID < c.Entries[j].ID
+	})
+	return &c
+}
+
+// ReadCatalog read Catalog snapshot from r
+func ReadCatalog(r io.Reader) (*Catalog, error) {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, WithMessage(err, "decode catalog failed")
+	}
+	if c.Format != CatalogFormatVersion {
+		return nil, WithError(fmt.Errorf("catalog format %d is not supported, expect %d", c.Format, CatalogFormatVersion), InvalidArgument)
+	}
+	return &c, nil
+}
+
+// WriteJSON write Catalog snapshot into w as indented json
+func (c *Catalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// CatalogChangeKind is the kind of `CatalogChange`
+type CatalogChangeKind string
+
+const (
+	// CatalogRemoved a MetaError is removed, NOTE: change code is reported as removed + added since code is a part of id
+	CatalogRemoved CatalogChangeKind = "removed"
+
+	// CatalogStatusChanged the http status of a MetaError is changed
+	CatalogStatusChanged CatalogChangeKind = "status_changed"
+
+	// CatalogMessageChanged the message of a MetaError is changed
+	CatalogMessageChanged CatalogChangeKind = "message_changed"
+
+	// CatalogAdded a new MetaError is added
+	CatalogAdded CatalogChangeKind = "added"
+
+	// CatalogDeprecated a MetaError is marked as deprecated
+	CatalogDeprecated CatalogChangeKind = "deprecated"
+
+	// CatalogUndeprecated the deprecation of a MetaError is withdrawn
+	CatalogUndeprecated CatalogChangeKind = "undeprecated"
+)
+
+// Breaking returns whether the change kind breaks published clients
+func (k CatalogChangeKind) Breaking() bool {
+	switch k {
+	case CatalogRemoved, CatalogStatusChanged, CatalogMessageChanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// CatalogChange describes a change of MetaError between two catalogs
+type CatalogChange struct {
+	ID       string            `json:"id"`
+	Kind     CatalogChangeKind `json:"kind"`
+	Breaking bool              `json:"breaking"`
+	Old      any               `json:"old,omitempty"`
+	New      any               `json:"new,omitempty"`
+}
+
+// CatalogDiff is the result of `DiffCatalog`
+type CatalogDiff struct {
+	OldVersion string          `json:"old_version"`
+	NewVersion string          `json:"new_version"`
+	Changes    []CatalogChange `json:"changes"`
+}
+
+// Breaking returns true if any breaking change exists
+func (d *CatalogDiff) Breaking() bool {
+	for _, c := range d.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffCatalog compare two catalogs and classify the changes, the result changes are sorted by id and kind
+func DiffCatalog(oldCatalog, newCatalog *Catalog) *CatalogDiff {
+	d := CatalogDiff{
+		OldVersion: oldCatalog.Version,
+		NewVersion: newCatalog.Version,
+	}
+	add := func(id string, kind CatalogChangeKind, o, n any) {
+		d.Changes = append(d.Changes, CatalogChange{
+			ID:       id,
+			Kind:     kind,
+			Breaking: kind.Breaking(),
+			Old:      o,
+			New:      n,
+		})
+	}
+	newEntries := make(map[string]CatalogEntry, len(newCatalog.Entries))
+	for _, e := range newCatalog.Entries {
+		newEntries[e.ID] = e
+	}
+	oldEntries := make(map[string]CatalogEntry, len(oldCatalog.Entries))
+	for _, oe := range oldCatalog.Entries {
+		oldEntries[oe.ID] = oe
+		ne, ok := newEntries[oe.ID]
+		if !ok {
+			add(oe.ID, CatalogRemoved, oe, nil)
+			continue
+		}
+		if oe.Status != ne.Status {
+			add(oe.ID, CatalogStatusChanged, oe.Status, ne.Status)
+		}
+		if oe.Message != ne.Message {
+			add(oe.ID, CatalogMessageChanged, oe.Message, ne.Message)
+		}
+		if oe.Deprecated == "" && ne.Deprecated != "" {
+			add(oe.ID, CatalogDeprecated, nil, ne.Deprecated)
+		}
+		if oe.Deprecated != "" && ne.Deprecated == "" {
+			add(oe.ID, CatalogUndeprecated, oe.Deprecated, nil)
+		}
+	}
+	for _, ne := range newCatalog.Entries {
+		if _, ok := oldEntries[ne.ID]; !ok {
+			add(ne.ID, CatalogAdded, nil, ne)
+		}
+	}
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		if d.Changes[i].ID != d.Changes[j].ID {
+			return d.Changes[i].ID < d.Changes[j].ID
+		}
+		return d.Changes[i].Kind < d.Changes[j].Kind
+	})
+	return &d
+}
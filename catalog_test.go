@@ -0,0 +1,81 @@
+package errors_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	c := errors.NewCatalog("v1.0.0")
+	assert.Equalf(t, "v1.0.0", c.Version, "version")
+	var found bool
+	for _, e := range c.Entries {
+		if e.ID == "myapp:github.com/ccmonky/errors:not_found(5)" {
+			found = true
+			assert.Equalf(t, 404, e.Status, "not found status")
+			assert.Equalf(t, "not found", e.Message, "not found message")
+		}
+		if e.ID == "myapp:deprecated_test:old_gone" {
+			assert.Equalf(t, "use gone", e.Deprecated, "deprecated reason")
+			assert.Equalf(t, "myapp:deprecated_test:gone", e.Replacement, "deprecated replacement")
+		}
+	}
+	assert.Truef(t, found, "not found in catalog")
+
+	var buf bytes.Buffer
+	assert.Nilf(t, c.WriteJSON(&buf), "write catalog")
+	c2, err := errors.ReadCatalog(&buf)
+	assert.Nilf(t, err, "read catalog")
+	assert.Equalf(t, c, c2, "read write catalog")
+	assert.Equalf(t, 0, len(errors.DiffCatalog(c, c2).Changes), "no changes")
+
+	_, err = errors.ReadCatalog(bytes.NewBufferString(`{"format":0}`))
+	assert.Truef(t, errors.Is(err, errors.InvalidArgument), "bad format")
+}
+
+func TestDiffCatalog(t *testing.T) {
+	oldCatalog := &errors.Catalog{
+		Version: "v1",
+		Entries: []errors.CatalogEntry{
+			{ID: "a:s:removed", Status: 404, Message: "removed"},
+			{ID: "a:s:status", Status: 404, Message: "status"},
+			{ID: "a:s:message", Status: 400, Message: "message"},
+			{ID: "a:s:deprecated", Status: 400, Message: "deprecated"},
+			{ID: "a:s:same", Status: 400, Message: "same"},
+			{ID: "a:s:undeprecated", Status: 400, Message: "undeprecated", Deprecated: "use same"},
+		},
+	}
+	newCatalog := &errors.Catalog{
+		Version: "v2",
+		Entries: []errors.CatalogEntry{
+			{ID: "a:s:status", Status: 410, Message: "status"},
+			{ID: "a:s:message", Status: 400, Message: "message changed"},
+			{ID: "a:s:deprecated", Status: 400, Message: "deprecated", Deprecated: "use same"},
+			{ID: "a:s:same", Status: 400, Message: "same"},
+			{ID: "a:s:added", Status: 500, Message: "added"},
+			{ID: "a:s:undeprecated", Status: 400, Message: "undeprecated"},
+		},
+	}
+	d := errors.DiffCatalog(oldCatalog, newCatalog)
+	assert.Truef(t, d.Breaking(), "breaking")
+	assert.Equalf(t, "v1", d.OldVersion, "old version")
+	assert.Equalf(t, "v2", d.NewVersion, "new version")
+	var kinds []string
+	for _, c := range d.Changes {
+		kinds = append(kinds, c.ID+"|"+string(c.Kind)+"|"+map[bool]string{true: "breaking", false: "compatible"}[c.Breaking])
+	}
+	assert.Equalf(t, []string{
+		"a:s:added|added|compatible",
+		"a:s:deprecated|deprecated|compatible",
+		"a:s:message|message_changed|breaking",
+		"a:s:removed|removed|breaking",
+		"a:s:status|status_changed|breaking",
+		"a:s:undeprecated|undeprecated|compatible",
+	}, kinds, "changes")
+
+	d = errors.DiffCatalog(oldCatalog, &errors.Catalog{Entries: append(oldCatalog.Entries, errors.CatalogEntry{ID: "a:s:added"})})
+	assert.Falsef(t, d.Breaking(), "only added")
+}
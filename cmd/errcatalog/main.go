@@ -0,0 +1,114 @@
+// Command errcatalog dumps and diffs MetaError catalog snapshots, usually used in CI to detect breaking changes
+// of published MetaErrors between releases.
+//
+// Usage:
+//
+//     errcatalog dump -version v1.2.0 > catalog.json
+//     errcatalog diff [-json] old.json new.json
+//
+// NOTE: `dump` only contains the MetaErrors linked into this command(the builtin codes), apps should dump their own
+// catalog with `errors.NewCatalog(version).WriteJSON(w)` in a binary which imports all their MetaErrors.
+//
+// `diff` exits with status 1 if any breaking change(removed, status changed, message changed) is found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ccmonky/errors"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprintln(stderr, "usage: errcatalog dump|diff [flags]")
+		return 2
+	}
+	switch args[0] {
+	case "dump":
+		return dump(args[1:], stdout, stderr)
+	case "diff":
+		return diff(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q, usage: errcatalog dump|diff [flags]\n", args[0])
+		return 2
+	}
+}
+
+func dump(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	version := fs.String("version", "", "release version of the catalog snapshot")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := errors.NewCatalog(*version).WriteJSON(stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	return 0
+}
+
+func diff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "output the diff as json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: errcatalog diff [-json] old.json new.json")
+		return 2
+	}
+	oldCatalog, err := readCatalog(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	newCatalog, err := readCatalog(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	d := errors.DiffCatalog(oldCatalog, newCatalog)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	} else {
+		for _, c := range d.Changes {
+			level := "compatible"
+			if c.Breaking {
+				level = "BREAKING"
+			}
+			fmt.Fprintf(stdout, "%-10s %-15s %s\n", level, c.Kind, c.ID)
+		}
+	}
+	if d.Breaking() {
+		return 1
+	}
+	return 0
+}
+
+func readCatalog(path string) (*errors.Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := errors.ReadCatalog(f)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "read catalog %s failed", path)
+	}
+	return c, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDump(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{name: "no command", args: nil, code: 2, stderr: "usage: errcatalog dump|diff [flags]\n"},
+		{name: "unknown command", args: []string{"xxx"}, code: 2, stderr: "unknown command \"xxx\", usage: errcatalog dump|diff [flags]\n"},
+		{name: "unknown flag", args: []string{"dump", "-xxx"}, code: 2},
+		{name: "dump", args: []string{"dump", "-version", "v1.2.0"}, code: 0},
+	}
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, &stdout, &stderr)
+		assert.Equalf(t, c.code, code, "%s: exit code", c.name)
+		if c.stderr != "" {
+			assert.Equalf(t, c.stderr, stderr.String(), "%s: stderr", c.name)
+		}
+		if code != 0 {
+			continue
+		}
+		catalog, err := errors.ReadCatalog(&stdout)
+		if assert.Nilf(t, err, "%s: read catalog", c.name) {
+			assert.Equalf(t, "v1.2.0", catalog.Version, "%s: version", c.name)
+			assert.Equalf(t, errors.NewCatalog("v1.2.0").Entries, catalog.Entries, "%s: entries", c.name)
+		}
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, c *errors.Catalog) string {
+		path := filepath.Join(dir, name)
+		var buf bytes.Buffer
+		assert.Nilf(t, c.WriteJSON(&buf), "write %s", name)
+		assert.Nilf(t, os.WriteFile(path, buf.Bytes(), 0o644), "write %s", name)
+		return path
+	}
+	v1 := write("v1.json", &errors.Catalog{Format: errors.CatalogFormatVersion, Version: "v1", Entries: []errors.CatalogEntry{
+		{ID: "a:s:removed", Status: 404, Message: "removed"},
+		{ID: "a:s:same", Status: 400, Message: "same"},
+	}})
+	v2 := write("v2.json", &errors.Catalog{Format: errors.CatalogFormatVersion, Version: "v2", Entries: []errors.CatalogEntry{
+		{ID: "a:s:same", Status: 400, Message: "same", Deprecated: "use added"},
+		{ID: "a:s:added", Status: 500, Message: "added"},
+	}})
+	v3 := write("v3.json", &errors.Catalog{Format: errors.CatalogFormatVersion, Version: "v3", Entries: []errors.CatalogEntry{
+		{ID: "a:s:same", Status: 400, Message: "same"},
+		{ID: "a:s:added", Status: 500, Message: "added"},
+	}})
+	unsupported := write("unsupported.json", &errors.Catalog{Format: 0, Version: "v0"})
+
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+	}{
+		{name: "same", args: []string{"diff", v1, v1}, code: 0, stdout: ""},
+		{name: "breaking", args: []string{"diff", v1, v2}, code: 1, stdout: "" +
+			"compatible added           a:s:added\n" +
+			"BREAKING   removed         a:s:removed\n" +
+			"compatible deprecated      a:s:same\n"},
+		{name: "undeprecated", args: []string{"diff", v2, v3}, code: 0, stdout: "compatible undeprecated    a:s:same\n"},
+		{name: "missing file", args: []string{"diff", v1, filepath.Join(dir, "missing.json")}, code: 2},
+		{name: "unsupported format", args: []string{"diff", unsupported, v1}, code: 2},
+		{name: "wrong args", args: []string{"diff", v1}, code: 2},
+		{name: "unknown flag", args: []string{"diff", "-xxx", v1, v2}, code: 2},
+	}
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, &stdout, &stderr)
+		assert.Equalf(t, c.code, code, "%s: exit code", c.name)
+		if c.code == 2 {
+			assert.NotEmptyf(t, stderr.String(), "%s: stderr", c.name)
+			continue
+		}
+		assert.Equalf(t, c.stdout, stdout.String(), "%s: stdout", c.name)
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equalf(t, 1, run([]string{"diff", "-json", v1, v2}, &stdout, &stderr), "json: exit code")
+	var d errors.CatalogDiff
+	if assert.Nilf(t, json.Unmarshal(stdout.Bytes(), &d), "json: unmarshal") {
+		assert.Equalf(t, "v1", d.OldVersion, "json: old version")
+		assert.Equalf(t, "v2", d.NewVersion, "json: new version")
+		assert.Lenf(t, d.Changes, 3, "json: changes")
+		assert.Truef(t, d.Breaking(), "json: breaking")
+	}
+}
@@ -31,4 +31,35 @@ var (
 	status = StatusAttr.Option
 )
 
+// builtinSeverities is the severity of builtin MetaErrors by code, it's served by `SeverityAttr` instead of being
+// attached on them, so that builtin MetaErrors keep their output
+var builtinSeverities = map[string]Severity{
+	"success(0)":             SeverityDebug,
+	"canceled(1)":            SeverityInfo,
+	"unknown(2)":             SeverityError,
+	"invalid_argument(3)":    SeverityInfo,
+	"deadline_exceeded(4)":   SeverityWarning,
+	"not_found(5)":           SeverityInfo,
+	"already_exists(6)":      SeverityInfo,
+	"permission_denied(7)":   SeverityInfo,
+	"resource_exhausted(8)":  SeverityWarning,
+	"failed_precondition(9)": SeverityInfo,
+	"aborted(10)":            SeverityWarning,
+	"out_of_range(11)":       SeverityInfo,
+	"unimplemented(12)":      SeverityError,
+	"internal(13)":           SeverityError,
+	"unavailable(14)":        SeverityError,
+	"data_loss(15)":          SeverityCritical,
+	"unauthenticated(16)":    SeverityInfo,
+}
+
+// builtinSeverity returns the severity of m if it's the meta of a builtin MetaError
+func builtinSeverity(m *Meta) (Severity, bool) {
+	if m == nil || m.source != source {
+		return SeverityDebug, false
+	}
+	s, ok := builtinSeverities[m.code]
+	return s, ok
+}
+
 type _pkgtype struct{}
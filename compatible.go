@@ -31,19 +31,35 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
+	"strings"
 
 	pkgerrors "github.com/pkg/errors"
 )
 
-// standard
-var (
-	// New is `errors.New`
-	New = errors.New
+// New imitate `github.com/pkg/errors.New`, returns an error with the supplied message and records the stack trace at
+// the point it was called
+func New(message string) error {
+	return &fundamental{
+		msg:   message,
+		stack: callers(),
+	}
+}
 
-	// Errorf is `fmt.Errorf`
-	Errorf = fmt.Errorf
-)
+// Errorf imitate `github.com/pkg/errors.Errorf`, formats according to a format specifier and returns the string as a
+// value that satisfies error, and records the stack trace at the point it was called
+//
+// NOTE: unlike pkg/errors, `%w` is supported as `fmt.Errorf`, errors wrapped by `%w`(even multiple) can be tested by
+// `Is` and `As`, and the single one can also be unwrapped by `Unwrap`
+func Errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	return &fundamental{
+		msg:     err.Error(),
+		wrapped: err,
+		stack:   callers(),
+	}
+}
 
 // github.com/pkg/errros
 var (
@@ -71,6 +87,112 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	return StackAttr.With(WithMessagef(err, format, args...), callers())
 }
 
+// fundamental is an error that has a message and a stack, but no caller
+type fundamental struct {
+	msg     string
+	wrapped error // NOTE: the result of `fmt.Errorf`, nil if created by `New`
+	*stack
+}
+
+func (f *fundamental) Error() string { return f.msg }
+
+// Unwrap returns the error wrapped by `%w` of `Errorf`, nil if not exists or multiple `%w` specified
+func (f *fundamental) Unwrap() error { return errors.Unwrap(f.wrapped) }
+
+// Is reports whether any error wrapped by `%w` of `Errorf` matches target
+func (f *fundamental) Is(target error) bool { return f.wrapped != nil && errors.Is(f.wrapped, target) }
+
+// As finds the first error wrapped by `%w` of `Errorf` that matches target
+func (f *fundamental) As(target any) bool { return f.wrapped != nil && errors.As(f.wrapped, target) }
+
+// Format implement fmt.Formatter, same as `github.com/pkg/errors`
+func (f *fundamental) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, f.msg)
+			f.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, f.msg)
+	case 'q':
+		fmt.Fprintf(s, "%q", f.msg)
+	}
+}
+
+// StackTrace returns the origin stack of the chain(see `github.com/pkg/errors.StackTrace`): the stack of the cause if
+// exists(e.g. created by `New`), otherwise the oldest one attached by `StackAttr`
+func (e *valueError) StackTrace() pkgerrors.StackTrace {
+	if s := originStack(e); s != nil {
+		return s.StackTrace()
+	}
+	return nil
+}
+
+// originStack returns the origin stack of err: the stack of the cause if exists, otherwise the oldest one attached by
+// `StackAttr`, returns nil if not found
+func originStack(err error) *stack {
+	switch cause := Cause(err).(type) {
+	case *fundamental:
+		return cause.stack
+	case interface{ StackTrace() pkgerrors.StackTrace }:
+		if _, ok := cause.(*valueError); !ok {
+			st := cause.StackTrace()
+			s := make(stack, len(st))
+			for i, f := range st {
+				s[i] = uintptr(f)
+			}
+			return &s
+		}
+	}
+	var oldest *stack
+	walk(err, 0, nil, func(n Node) WalkAction {
+		if s, ok := n.Value.(*stack); ok && n.Key == StackAttr.Key() {
+			oldest = s
+		}
+		return WalkSkipChildren
+	})
+	return oldest
+}
+
+// pkgErrorsMessage formats `MessageAttr` as `msg: inner` in `PkgErrors` mode
+func pkgErrorsMessage(a FormatAttr) string {
+	if a.Inner == "" {
+		return a.Text
+	}
+	return a.Text + ": " + a.Inner
+}
+
+// formatPkgErrors writes `%+v` of err in `PkgErrors` mode: `%+v` of the base of the chain(see `chainBase`), then messages and stacks from the
+// oldest to the latest, which is the same as the output of `github.com/pkg/errors`
+func formatPkgErrors(s io.Writer, err error) {
+	var nodes []Node
+	walk(err, 0, nil, func(n Node) WalkAction {
+		nodes = append(nodes, n)
+		return WalkSkipChildren
+	})
+	var sb strings.Builder
+	if base := chainBase(err); base != empty && base != nil {
+		fmt.Fprintf(&sb, "%+v", base)
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		switch v := nodes[i].Value.(type) {
+		case *stack:
+			fmt.Fprintf(&sb, "%+v", v)
+		case string:
+			if nodes[i].Key == MessageAttr.Key() {
+				if sb.Len() > 0 {
+					sb.WriteByte('\n')
+				}
+				sb.WriteString(v)
+			}
+		}
+	}
+	io.WriteString(s, sb.String())
+}
+
 // stack represents a stack of program counters.
 type stack []uintptr
 
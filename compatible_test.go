@@ -0,0 +1,34 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPkgErrorsMode(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	ours, theirs := errors.Wrap(io.EOF, "w1"), pkgerrors.Wrap(io.EOF, "w1")
+	ours, theirs = errors.Wrapf(ours, "w%d", 2), pkgerrors.Wrapf(theirs, "w%d", 2)
+	ours, theirs = errors.WithMessage(ours, "m3"), pkgerrors.WithMessage(theirs, "m3")
+	ours, theirs = errors.WithStack(ours), pkgerrors.WithStack(theirs)
+	assert.Equal(t, "m3: w2: w1: EOF", ours.Error())
+	for _, format := range []string{"%s", "%v", "%q", "%+v"} {
+		assert.Equalf(t, fmt.Sprintf(format, theirs), fmt.Sprintf(format, ours), format)
+	}
+
+	ours, theirs = errors.WithError(errors.WithMessage(errors.New("cause"), "load user"), errors.NotFound), pkgerrors.WithMessage(pkgerrors.New("cause"), "load user")
+	assert.Equalf(t, "load user: cause", ours.Error(), "non-message attrs omitted")
+	assert.Equalf(t, fmt.Sprintf("%+v", theirs), fmt.Sprintf("%+v", ours), "same as pkg/errors WithMessage")
+	assert.Equal(t, "msg", errors.WithMessage(errors.Empty(), "msg").Error())
+
+	errors.SetFormatMode(errors.Default)
+	assert.Equal(t, "EOF:msg={w1}:stack={}", errors.Wrap(io.EOF, "w1").Error())
+	assert.Equalf(t, "w1: EOF", errors.With(errors.Wrap(io.EOF, "w1"), errors.FormatModeAttr.Option(errors.PkgErrors)).Error(), "per error")
+}
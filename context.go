@@ -0,0 +1,198 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CtxAttrFieldPrefix is the prefix of fields extracted from `CtxAttr` in `Map`
+var CtxAttrFieldPrefix = "ctx."
+
+// ContextExtractor extracts a named value from context.Context, returns nil if not found
+type ContextExtractor func(ctx context.Context) any
+
+// RegisterContextExtractor register an extractor which pulls named value(e.g. request id, trace id, tenant, user)
+// from the context attached by `CtxAttr`, the extracted values will be used in `Error()`, `Map`, json and slog output
+// instead of the whole context, register with the same name will override the previous one
+func RegisterContextExtractor(name string, extractor ContextExtractor) {
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	n := len(contextExtractors)
+	for i, ne := range contextExtractors {
+		if ne.name == name {
+			extractors := append(contextExtractors[:0:0], contextExtractors...)
+			extractors[i].extractor = extractor
+			contextExtractors = extractors
+			return
+		}
+	}
+	contextExtractors = append(contextExtractors[:n:n], namedExtractor{name, extractor})
+}
+
+// RegisterContextValue register an extractor which extract `ctx.Value(key)` as the named value
+func RegisterContextValue(name string, key any) {
+	RegisterContextExtractor(name, func(ctx context.Context) any {
+		return ctx.Value(key)
+	})
+}
+
+// UnregisterContextExtractor remove the extractor with name
+func UnregisterContextExtractor(name string) {
+	contextExtractorsLock.Lock()
+	defer contextExtractorsLock.Unlock()
+	for i, ne := range contextExtractors {
+		if ne.name == name {
+			contextExtractors = append(contextExtractors[:i:i], contextExtractors[i+1:]...)
+			return
+		}
+	}
+}
+
+// ExtractContext returns the name:value map of all registered extractors, nil values are ignored
+func ExtractContext(ctx context.Context) map[string]any {
+	m := make(map[string]any)
+	extractContext(ctx, func(name string, v any) {
+		m[name] = v
+	})
+	return m
+}
+
+// formatContext format ctx as `name=value;name=value` in extractors registration order
+func formatContext(ctx context.Context) string {
+	var parts []string
+	extractContext(ctx, func(name string, v any) {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, v))
+	})
+	return strings.Join(parts, ";")
+}
+
+// extractContext calls fn with the non-nil values extracted from ctx in extractors registration order, extractors are
+// called outside the lock, so that they can register or unregister extractors
+func extractContext(ctx context.Context, fn func(name string, v any)) {
+	if ctx == nil {
+		return
+	}
+	contextExtractorsLock.RLock()
+	extractors := contextExtractors
+	contextExtractorsLock.RUnlock()
+	for _, ne := range extractors {
+		if v := ne.extractor(ctx); v != nil {
+			fn(ne.name, v)
+		}
+	}
+}
+
+// WithContextAttrs returns a copy of ctx which carries pending options(appended to the options carried by ctx), they
+// will be applied on errors by `FromContext`, `WithErrorCtx`, `AdaptCtx` and `Adapt`(if err carries ctx by `CtxAttr`),
+// usually used to set tenant, request id, route... at the edge, so that errors created deeper can carry them
+//
+// Usage:
+//
+//     ctx = errors.WithContextAttrs(ctx, TenantAttr.Option(tenant), RouteAttr.Option(route))
+//     // ... deeper in the call stack
+//     return errors.WithErrorCtx(ctx, err, errors.NotFound)
+func WithContextAttrs(ctx context.Context, opts ...Option) context.Context {
+	parent, _ := ctx.Value(contextOptionsKey{}).(*contextOptions)
+	return context.WithValue(ctx, contextOptionsKey{}, &contextOptions{opts: opts, parent: parent})
+}
+
+// ContextOptions returns the pending options carried by ctx
+func ContextOptions(ctx context.Context) []Option {
+	var opts []Option
+	for _, co := range pendingContextOptions(ctx, nil) {
+		opts = append(opts, co.opts...)
+	}
+	return opts
+}
+
+// FromContext apply the pending options carried by ctx on err, options which have been applied on err(anywhere in the
+// chain) by `FromContext` are skipped, so that it's safe to be called multiple times on the same err, e.g.
+// `WithErrorCtx` deep in the call stack and then `AdaptCtx` at the edge
+func FromContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	pending := pendingContextOptions(ctx, appliedContextOptions(err))
+	if len(pending) == 0 {
+		return err
+	}
+	for _, co := range pending {
+		err = With(err, co.opts...)
+	}
+	return &valueError{err, contextOptionsAppliedKey{}, ctx.Value(contextOptionsKey{})}
+}
+
+// pendingContextOptions returns the options carried by ctx which are not in applied, from the oldest to the latest
+func pendingContextOptions(ctx context.Context, applied map[*contextOptions]bool) []*contextOptions {
+	if ctx == nil {
+		return nil
+	}
+	var pending []*contextOptions
+	co, _ := ctx.Value(contextOptionsKey{}).(*contextOptions)
+	for ; co != nil && !applied[co]; co = co.parent {
+		pending = append(pending, co)
+	}
+	for i, j := 0, len(pending)-1; i < j; i, j = i+1, j-1 {
+		pending[i], pending[j] = pending[j], pending[i]
+	}
+	return pending
+}
+
+// appliedContextOptions returns the options(and their parents) applied on err by `FromContext`, the markers are looked
+// up in nested error chains and through foreign wrappers
+func appliedContextOptions(err error) map[*contextOptions]bool {
+	var applied map[*contextOptions]bool
+	var lookup func(err error)
+	lookup = func(err error) {
+		for err != nil {
+			ve, ok := err.(*valueError)
+			if !ok {
+				err = stderrors.Unwrap(err)
+				continue
+			}
+			if _, ok := ve.key.(contextOptionsAppliedKey); ok {
+				for co, _ := ve.val.(*contextOptions); co != nil && !applied[co]; co = co.parent {
+					if applied == nil {
+						applied = make(map[*contextOptions]bool)
+					}
+					applied[co] = true
+				}
+			} else if isChain(ve.val) {
+				lookup(ve.val.(error))
+			}
+			err = ve.error
+		}
+	}
+	lookup(err)
+	return applied
+}
+
+// WithErrorCtx attach e on err by `WithError`, then apply the pending options carried by ctx
+func WithErrorCtx(ctx context.Context, err, e error) error {
+	return FromContext(ctx, WithError(err, e))
+}
+
+type contextOptionsKey struct{}
+
+// contextOptions is the options of a `WithContextAttrs` call, parent is the options carried by the ctx before it
+type contextOptions struct {
+	opts   []Option
+	parent *contextOptions
+}
+
+// contextOptionsAppliedKey is the key of the node which marks the options of ctx(and their parents) applied by
+// `FromContext`, the node is invisible: skipped by `Walk`, formatting and json
+type contextOptionsAppliedKey struct{}
+
+type namedExtractor struct {
+	name      string
+	extractor ContextExtractor
+}
+
+var (
+	contextExtractors     []namedExtractor // NOTE: never modified in place, replaced on write
+	contextExtractorsLock sync.RWMutex
+)
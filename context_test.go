@@ -0,0 +1,147 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey string
+
+func TestContextExtractor(t *testing.T) {
+	errors.RegisterContextValue("request_id", ctxKey("request_id"))
+	errors.RegisterContextExtractor("tenant", func(ctx context.Context) any {
+		return ctx.Value(ctxKey("tenant"))
+	})
+	defer errors.UnregisterContextExtractor("request_id")
+	defer errors.UnregisterContextExtractor("tenant")
+
+	ctx := context.WithValue(context.TODO(), ctxKey("request_id"), "r1")
+	ctx = context.WithValue(ctx, ctxKey("tenant"), "t1")
+	ctx = context.WithValue(ctx, ctxKey("secret"), "should not leak")
+	err := errors.WithCtx(errors.WithError(errors.New("xxx"), errors.NotFound), ctx)
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:ctx={request_id=r1;tenant=t1}", err.Error(), "error string")
+	assert.Equalf(t, map[string]any{"request_id": "r1", "tenant": "t1"}, errors.ExtractContext(ctx), "extract")
+
+	m := errors.Map(err)
+	assert.Equalf(t, "r1", m["ctx.request_id"], "map request id")
+	assert.Equalf(t, "t1", m["ctx.tenant"], "map tenant")
+	assert.NotContainsf(t, m, "ctx.secret", "map secret")
+	m = errors.NewAttrs(errors.CtxAttr).Map(err)
+	assert.Equalf(t, "r1", m["ctx.request_id"], "attrs map request id")
+
+	data, merr := json.Marshal(errors.WithCtx(errors.New("xxx"), ctx))
+	assert.Nilf(t, merr, "marshal")
+	assert.JSONEq(t, `{"error":{},"key":"ctx","value":{"request_id":"r1","tenant":"t1"}}`, string(data), "json")
+
+	errors.RegisterContextValue("tenant", ctxKey("request_id"))
+	assert.Equalf(t, "xxx:ctx={request_id=r1;tenant=r1}", errors.WithCtx(errors.New("xxx"), ctx).Error(), "override")
+	errors.UnregisterContextExtractor("tenant")
+	assert.Equalf(t, "xxx:ctx={request_id=r1}", fmt.Sprintf("%v", errors.WithCtx(errors.New("xxx"), ctx)), "unregister")
+}
+
+func TestContextAttrs(t *testing.T) {
+	ctx := errors.WithContextAttrs(context.TODO(), errors.MessageOption("tenant=t1"))
+	ctx = errors.WithContextAttrs(ctx, errors.CallerOption("route=/users"))
+	assert.Equalf(t, 2, len(errors.ContextOptions(ctx)), "pending options")
+	assert.Equalf(t, 0, len(errors.ContextOptions(context.TODO())), "no pending options")
+
+	err := errors.FromContext(ctx, errors.New("xxx"))
+	assert.Equalf(t, "xxx:msg={tenant=t1}:caller={route=/users}", err.Error(), "from context")
+
+	err = errors.WithErrorCtx(ctx, errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={tenant=t1}:caller={route=/users}", err.Error(), "with error ctx")
+
+	err = errors.AdaptCtx(ctx, errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, "tenant=t1", errors.MessageAttr.Get(err), "adapt ctx message")
+	assert.Equalf(t, "route=/users", errors.CallerAttr.Get(err), "adapt ctx caller")
+	assert.Equalf(t, []string{"errors_test.TestContextAttrs:60", "route=/users"}, errors.CallerAttr.GetAll(err), "adapt ctx callers")
+	assert.Equalf(t, errors.NotFound, errors.GetLatestMetaError(err), "adapt ctx meta")
+
+	err = errors.Adapt(errors.WithCtx(errors.New("xxx"), ctx), errors.NotFound)
+	assert.Equalf(t, "tenant=t1", errors.MessageAttr.Get(err), "adapt with ctx attr")
+	err = errors.Adapt(errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, "", errors.MessageAttr.Get(err), "adapt without ctx")
+}
+
+func TestContextAttrsAppliedOnce(t *testing.T) {
+	ctx := errors.WithContextAttrs(context.TODO(), errors.MessageOption("tenant=t1"), errors.StatusOption(409))
+	err := errors.WithErrorCtx(ctx, errors.New("xxx"), errors.NotFound) // NOTE: deep in the call stack
+	err = errors.AdaptCtx(ctx, err, errors.Unknown)                     // NOTE: at the edge
+	assert.Equalf(t, []string{"tenant=t1"}, errors.MessageAttr.GetAll(err), "applied once")
+	assert.Equalf(t, 409, errors.StatusAttr.Get(err), "status of ctx")
+	assert.Equalf(t, errors.NotFound, errors.GetLatestMetaError(err), "not adapted")
+
+	err = errors.FromContext(ctx, errors.FromContext(ctx, errors.New("xxx")))
+	assert.Equalf(t, "xxx:msg={tenant=t1}:status={409}", err.Error(), "from context twice")
+	err = errors.Adapt(errors.WithCtx(err, ctx), errors.NotFound)
+	assert.Equalf(t, []string{"tenant=t1"}, errors.MessageAttr.GetAll(err), "adapt with ctx attr")
+
+	wrap := errors.WithContextAttrs(context.TODO(), func(err error) error { return fmt.Errorf("wrap: %w", err) })
+	assert.Equalf(t, "wrap: xxx", errors.FromContext(wrap, errors.New("xxx")).Error(), "non-attr options are applied as is")
+}
+
+func TestContextExtractorReentrant(t *testing.T) {
+	errors.RegisterContextExtractor("lazy", func(ctx context.Context) any {
+		errors.RegisterContextValue("request_id", ctxKey("request_id")) // NOTE: deadlocked if called under the lock
+		return "lazy"
+	})
+	defer errors.UnregisterContextExtractor("lazy")
+	defer errors.UnregisterContextExtractor("request_id")
+	ctx := context.WithValue(context.TODO(), ctxKey("request_id"), "r1")
+	done := make(chan map[string]any)
+	go func() {
+		done <- errors.ExtractContext(ctx)
+	}()
+	select {
+	case m := <-done:
+		assert.Equalf(t, map[string]any{"lazy": "lazy"}, m, "extractors registered during extraction are used next time")
+	case <-time.After(time.Second):
+		t.Fatal("register extractor in extractor deadlocked")
+	}
+	assert.Equalf(t, "xxx:ctx={lazy=lazy;request_id=r1}", errors.WithCtx(errors.New("xxx"), ctx).Error(), "registration order")
+}
+
+func TestContextAttrsAppliedOnErr(t *testing.T) {
+	var observed []string
+	errors.SetObserver(errors.ObserverFunc(func(point errors.ObservePoint, err error) {
+		observed = append(observed, err.Error())
+	}))
+	defer errors.SetObserver(nil)
+	errors.SetObserveWithError(true)
+	defer errors.SetObserveWithError(false)
+	var deprecated []errors.MetaError
+	errors.SetDeprecatedHook(func(me errors.MetaError, d *errors.Deprecation) {
+		deprecated = append(deprecated, me)
+	})
+	defer errors.SetDeprecatedHook(nil)
+
+	ctx := errors.WithContextAttrs(context.TODO(), errors.ErrorOption(testOldGone))
+	err := errors.FromContext(ctx, errors.New("xxx"))
+	err = errors.FromContext(ctx, err)
+	expect := "xxx:error={meta={source=deprecated_test;code=old_gone}:deprecated={use gone;replacement=myapp:deprecated_test:gone}}"
+	assert.Equalf(t, expect, err.Error(), "applied once")
+	assert.Equalf(t, []string{expect}, observed, "the returned error observed")
+	assert.Equalf(t, []errors.MetaError{testOldGone}, deprecated, "hook called once")
+
+	child := errors.WithContextAttrs(ctx, errors.MessageOption("child"))
+	sibling := errors.WithContextAttrs(ctx, errors.MessageOption("sibling"))
+	err = errors.FromContext(sibling, errors.FromContext(child, errors.New("xxx")))
+	assert.Equalf(t, []string{"child", "sibling"}, errors.MessageAttr.GetAll(err), "options of parent applied once")
+	assert.Equalf(t, 2, len(deprecated), "parent applied once")
+	err = errors.FromContext(ctx, fmt.Errorf("wrap: %w", err))
+	assert.Equalf(t, 2, len(deprecated), "applied behind a foreign wrapper")
+	assert.Equalf(t, err, errors.FromContext(context.TODO(), err), "nothing pending")
+
+	data, merr := json.Marshal(errors.FromContext(child, errors.WithMessage(errors.New("xxx"), "ours")))
+	assert.Nilf(t, merr, "marshal")
+	assert.NotContainsf(t, string(data), "contextOptionsAppliedKey", "marker not marshaled")
+	err = errors.FromContext(child, errors.New("xxx"))
+	assert.Equalf(t, err.Error(), errors.Import(err).Error(), "marker kept by import")
+	assert.Equalf(t, errors.Import(err), errors.FromContext(child, errors.Import(err)), "applied after import")
+}
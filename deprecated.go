@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// DeprecatedAttr used to attach `*Deprecation` on MetaError, usually by `Deprecated` option of `NewMetaError`
+var DeprecatedAttr = NewAttr[*Deprecation]("deprecated", WithAttrDescription("deprecation info of meta error as an attr"))
+
+// Deprecation describes why a MetaError is retired and which MetaError should be used instead
+type Deprecation struct {
+	// Reason why the MetaError is deprecated
+	Reason string
+
+	// Replacement the MetaError used instead, nil means no replacement
+	Replacement MetaError
+}
+
+// Deprecated returns an `Option` used by `NewMetaError` to mark the MetaError as deprecated
+//
+// Usage:
+//
+//     var OldNotFound = errors.NewMetaError(source, "old_not_found", "not found", errors.Deprecated("use NotFound", errors.NotFound))
+func Deprecated(reason string, replacement MetaError) Option {
+	return DeprecatedAttr.Option(&Deprecation{
+		Reason:      reason,
+		Replacement: replacement,
+	})
+}
+
+func (d *Deprecation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"reason":      d.Reason,
+		"replacement": d.replacementID(),
+	})
+}
+
+func (d *Deprecation) String() string {
+	if d.Replacement == nil {
+		return d.Reason
+	}
+	return d.Reason + ";replacement=" + d.replacementID()
+}
+
+func (d *Deprecation) replacementID() string {
+	if d.Replacement == nil {
+		return ""
+	}
+	return MetaID(d.Replacement.App(), d.Replacement.Source(), d.Replacement.Code())
+}
+
+// IsDeprecated test if me is marked as deprecated
+func IsDeprecated(me MetaError) bool {
+	if me == nil {
+		return false
+	}
+	return DeprecatedAttr.Get(me) != nil
+}
+
+// ResolveDeprecated follows the replacement chain of deprecated me and returns the first non-deprecated MetaError,
+// returns me itself if me is not deprecated or has no replacement
+func ResolveDeprecated(me MetaError) MetaError {
+	for i := 0; i < maxDeprecationDepth && me != nil; i++ {
+		d := DeprecatedAttr.Get(me)
+		if d == nil || d.Replacement == nil {
+			return me
+		}
+		me = d.Replacement
+	}
+	return me
+}
+
+// SetDeprecatedHook set the hook called whenever a deprecated MetaError is attached via `WithError` or `Adapt`,
+// usually used to log or count the usages of deprecated MetaErrors, nil means no hook
+func SetDeprecatedHook(fn func(me MetaError, d *Deprecation)) {
+	deprecatedHookLock.Lock()
+	defer deprecatedHookLock.Unlock()
+	deprecatedHook = fn
+}
+
+func notifyDeprecated(e error) {
+	deprecatedHookLock.RLock()
+	hook := deprecatedHook
+	deprecatedHookLock.RUnlock()
+	if hook == nil {
+		return
+	}
+	me, ok := e.(MetaError)
+	if !ok {
+		return
+	}
+	if d := DeprecatedAttr.Get(me); d != nil {
+		hook(me, d)
+	}
+}
+
+// NOTE: used to avoid endless loop if replacements are cyclic
+const maxDeprecationDepth = 16
+
+var (
+	deprecatedHook     func(MetaError, *Deprecation)
+	deprecatedHookLock sync.RWMutex
+)
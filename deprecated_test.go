@@ -0,0 +1,77 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testGone     = errors.NewMetaError("deprecated_test", "gone", "gone", errors.StatusOption(http.StatusGone))
+	testOldGone  = errors.NewMetaError("deprecated_test", "old_gone", "gone", errors.Deprecated("use gone", testGone))
+	testOlderOne = errors.NewMetaError("deprecated_test", "older_gone", "gone", errors.Deprecated("use old_gone", testOldGone))
+)
+
+func TestDeprecated(t *testing.T) {
+	assert.Falsef(t, errors.IsDeprecated(testGone), "gone not deprecated")
+	assert.Truef(t, errors.IsDeprecated(testOldGone), "old_gone deprecated")
+	assert.Equalf(t, "use gone", errors.DeprecatedAttr.Get(testOldGone).Reason, "reason")
+	assert.Equalf(t, testGone, errors.ResolveDeprecated(testOlderOne), "resolve chain")
+	assert.Equalf(t, testGone, errors.ResolveDeprecated(testGone), "resolve not deprecated")
+	assert.Equalf(t, "meta={source=deprecated_test;code=old_gone}:deprecated={use gone;replacement=myapp:deprecated_test:gone}", testOldGone.Error(), "error string")
+
+	data, err := json.Marshal(errors.AllMetaErrors()["myapp:deprecated_test:old_gone"])
+	assert.Nilf(t, err, "marshal old_gone")
+	assert.JSONEq(t, `{
+		"error": {
+			"key": "meta",
+			"value": {
+				"meta.app": "myapp",
+				"meta.code": "old_gone",
+				"meta.message": "gone",
+				"meta.source": "deprecated_test"
+			}
+		},
+		"key": "deprecated",
+		"value": {
+			"reason": "use gone",
+			"replacement": "myapp:deprecated_test:gone"
+		}
+	}`, string(data), "catalog export")
+}
+
+func TestDeprecatedHook(t *testing.T) {
+	var hooked []string
+	errors.SetDeprecatedHook(func(me errors.MetaError, d *errors.Deprecation) {
+		hooked = append(hooked, me.Code()+":"+d.Reason)
+	})
+	defer errors.SetDeprecatedHook(nil)
+
+	errors.WithError(errors.New("xxx"), testGone)
+	assert.Equalf(t, 0, len(hooked), "not deprecated")
+	errors.WithError(errors.New("xxx"), testOldGone)
+	assert.Equalf(t, []string{"old_gone:use gone"}, hooked, "with error")
+	errors.With(errors.New("xxx"), errors.ErrorOption(testOlderOne))
+	assert.Equalf(t, []string{"old_gone:use gone", "older_gone:use old_gone"}, hooked, "error option")
+	errors.Adapt(errors.New("xxx"), testOldGone)
+	assert.Equalf(t, 3, len(hooked), "adapt")
+	errors.WithError(nil, testOldGone)
+	assert.Equalf(t, 3, len(hooked), "nil error")
+}
+
+func TestAdapterReplaceDeprecated(t *testing.T) {
+	adapter := errors.NewAdapter(errors.WithReplaceDeprecated())
+	err := adapter.Adapt(errors.New("xxx"), testOlderOne)
+	assert.Equalf(t, testGone, errors.GetLatestMetaError(err), "replace guard")
+	assert.Equalf(t, 410, errors.StatusAttr.Get(err), "replace guard status")
+
+	err = adapter.Adapt(errors.WithError(errors.New("xxx"), testOldGone), errors.Unknown)
+	assert.Equalf(t, testGone, errors.GetLatestMetaError(err), "replace latest")
+	assert.Truef(t, errors.Is(err, testOldGone), "still is old_gone")
+
+	err = errors.NewAdapter().Adapt(errors.WithError(errors.New("xxx"), testOldGone), errors.Unknown)
+	assert.Equalf(t, testOldGone, errors.GetLatestMetaError(err), "not replace by default")
+}
@@ -38,9 +38,6 @@ type Error interface {
 
 // helper functions for attrs
 var (
-	WithError   = ErrorAttr.With
-	ErrorOption = ErrorAttr.Option
-
 	WithMeta   = MetaAttr.With
 	MetaOption = MetaAttr.Option
 
@@ -57,6 +54,31 @@ var (
 	MessageOption = MessageAttr.Option
 )
 
+// WithError attach e on err by `ErrorAttr`, the deprecated hook will be called if e is a deprecated MetaError,
+// and the result will be observed if `SetObserveWithError(true)`
+func WithError(err, e error) error {
+	if err == nil {
+		return nil
+	}
+	err = attachError(err, e)
+	if observeWithError() {
+		observe(ObserveWithError, err)
+	}
+	return err
+}
+
+func attachError(err, e error) error {
+	notifyDeprecated(e)
+	return ErrorAttr.With(err, e)
+}
+
+// ErrorOption returns an `Option` which attach e by `WithError`
+func ErrorOption(e error) Option {
+	return func(err error) error {
+		return WithError(err, e)
+	}
+}
+
 // With used to attach multiple values on error with options
 func With(err error, opts ...Option) error {
 	if err == nil {
@@ -134,6 +156,9 @@ type valueError struct {
 }
 
 func (e *valueError) MarshalJSON() ([]byte, error) {
+	if _, ok := e.key.(contextOptionsAppliedKey); ok {
+		return json.Marshal(e.error)
+	}
 	key := fmt.Sprintf("%v", e.key)
 	if ks, ok := e.key.(*string); ok {
 		key = *ks
@@ -143,7 +168,11 @@ func (e *valueError) MarshalJSON() ([]byte, error) {
 		return nil, WithMessagef(err, "marshal error %v failed", e.error)
 	}
 	var rawError json.RawMessage = data
-	data, err = json.Marshal(e.val)
+	var val = e.val
+	if ctx, ok := val.(context.Context); ok {
+		val = ExtractContext(ctx) // NOTE: only marshal values extracted by registered extractors
+	}
+	data, err = json.Marshal(val)
 	if err != nil {
 		return nil, WithMessagef(err, "marshal val %v failed", e.val)
 	}
@@ -165,26 +194,24 @@ func (e *valueError) Value(key any) any {
 	return Get(e, key)
 }
 
+// Get get the latest value of key recursively, values which are error chains are looked up in `Walk` order, and
+// values of other `Value` implementations(e.g. context.Context) are looked up by their `Value`
 func Get(m error, key any) any {
-	for {
-		switch tm := m.(type) {
-		case *valueError:
-			if key == tm.key {
-				return tm.val
-			}
-			if vg, ok := tm.val.(valueGetter); ok {
-				value := vg.Value(key)
-				if value != nil {
-					return value
-				}
+	var value any
+	walk(m, 0, nil, func(n Node) WalkAction {
+		if n.Key == key {
+			value = n.Value
+			return WalkStop
+		}
+		if vg, ok := n.Value.(valueGetter); ok && !isChain(n.Value) { // NOTE: error chain values are walked as children
+			if v := vg.Value(key); v != nil {
+				value = v
+				return WalkStop
 			}
-			m = tm.error
-		case *emptyError:
-			return nil
-		default:
-			return nil
 		}
-	}
+		return WalkContinue
+	})
+	return value
 }
 
 // Values get all values of key recursively
@@ -192,35 +219,26 @@ func (e *valueError) Values(key any) []any {
 	return GetAll(e, key)
 }
 
-// GetAll get all values of key recursively
+// GetAll get all values of key recursively, from the oldest to the latest
 func GetAll(m error, key any) []any {
 	var all []any
-	for {
-		switch tm := m.(type) {
-		case *valueError:
-			if key == tm.key {
-				all = append(all, tm.val)
-			}
-			if vg, ok := tm.val.(valuesGetter); ok {
-				values := vg.Values(key)
-				if len(values) > 0 {
-					all = append(all, values...)
-				}
-			} else {
-				if vg, ok := tm.val.(valueGetter); ok {
-					value := vg.Value(key)
-					if value != nil {
-						all = append(all, value)
-					}
-				}
+	walk(m, 0, nil, func(n Node) WalkAction {
+		if n.Key == key {
+			all = append(all, n.Value)
+		}
+		if isChain(n.Value) { // NOTE: error chain values are walked as children
+			return WalkContinue
+		}
+		if vg, ok := n.Value.(valuesGetter); ok {
+			all = append(all, reverse(vg.Values(key))...)
+		} else if vg, ok := n.Value.(valueGetter); ok {
+			if value := vg.Value(key); value != nil {
+				all = append(all, value)
 			}
-			m = tm.error
-		case *emptyError:
-			return reverse(all)
-		default:
-			return reverse(all)
 		}
-	}
+		return WalkContinue
+	})
+	return reverse(all)
 }
 
 type valueGetter interface {
@@ -234,52 +252,56 @@ type valuesGetter interface {
 // String returns string representation for valueMeta
 // NOTE: if key type is *string, will use *key when print
 func (e *valueError) Error() string {
-	return formatByMode(e)
+	return formatChain(e, chainFormatMode(e))
 }
 
 func (e *valueError) String() string {
-	return formatByMode(e)
+	return formatChain(e, chainFormatMode(e))
 }
 
-func formatByMode(e *valueError) string {
-	formatModeLock.RLock()
-	mode := formatMode
-	formatModeLock.RUnlock()
-	errStr := e.error.Error() + ":"
-	if e.error == empty {
-		errStr = ""
-	}
-	switch mode {
-	case Simplified:
-		return errStr + "*={*}"
-	case NoValue:
-		if sp, ok := e.key.(*string); ok {
-			return errStr + *sp + "={*}"
-		}
-		return errStr + fmt.Sprintf("%v={*}", e.key)
-	default:
-		if sp, ok := e.key.(*string); ok {
-			return errStr + fmt.Sprintf("%s={%v}", *sp, e.val)
-		}
-		return errStr + fmt.Sprintf("%v={%v}", e.key, e.val)
+// displayValue returns the value used for formatting, context.Context will be displayed as the extracted values only
+func displayValue(v any) any {
+	if ctx, ok := v.(context.Context); ok {
+		return formatContext(ctx)
 	}
+	return v
 }
 
 // Format implement fmt.Formatter
 func (e *valueError) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, FormatTree(e))
+			return
+		}
 		if s.Flag('+') {
+			switch chainFormatMode(e) {
+			case Tree:
+				io.WriteString(s, FormatTree(e))
+				return
+			case PkgErrors:
+				formatPkgErrors(s, e)
+				return
+			}
+			if _, ok := e.key.(contextOptionsAppliedKey); ok {
+				fmt.Fprintf(s, "%+v", e.error)
+				return
+			}
 			fmt.Fprintf(s, "%+v\n", e.error)
 			var key = e.key
 			if sp, ok := e.key.(*string); ok {
 				key = *sp
 			}
-			io.WriteString(s, fmt.Sprintf("%+v={%+v}", key, e.val))
+			io.WriteString(s, fmt.Sprintf("%+v={%+v}", key, displayValue(e.val)))
 			return
 		}
 		fallthrough
 	case 's', 'q':
+		if verb == 'q' && chainFormatMode(e) == PkgErrors {
+			fmt.Fprintf(s, "%q", e.Error())
+			return
+		}
 		io.WriteString(s, e.Error())
 	}
 }
@@ -321,6 +343,11 @@ func (e *valueError) Unwrap() error {
 	return e.error
 }
 
+// Cause used to response to `github.com/pkg/errors.Cause`
+func (e *valueError) Cause() error {
+	return e.error
+}
+
 // Is implement errors.Is for valueError, to test if a `valueError` wrap from target, used for error assertion
 //
 // Usage:
@@ -342,88 +369,109 @@ func (e *valueError) UnwrapAll() (any, any, error) {
 // Options used to get `[]Option` attached on err
 func Options(err error) []Option {
 	var unwrapOpts []Option
-	for err != nil {
-		uv, ok := err.(interface {
-			UnwrapAll() (any, any, error)
-		})
-		if !ok {
-			break
-		}
-		var k, v any
-		k, v, err = uv.UnwrapAll()
+	walk(err, 0, nil, func(n Node) WalkAction {
+		k, v := n.Key, n.Value
 		unwrapOpts = append(unwrapOpts, func(e error) error {
 			return &valueError{e, k, v}
 		})
-	}
+		return WalkSkipChildren
+	})
 	return reverse(unwrapOpts)
 }
 
 // Map unwrap all to get `name:value` map of all attrs
 //
 // NOTE:
-// 1. unlike Get or `GetAll`, Map do not traversal the value of `valueError`
+// 1. unlike Get or `GetAll`, Map only traversal values which are error chains(see `Walk`), values of other `Value`
+//    implementations(e.g. context.Context) are not looked up
 // 2. result will not contain the value if key type is not *string
 // 3. if meta exists, then app, source and message fields will be added into result
 // 4. if key's name duplicates, the result will only contains the latest value
+// 5. if ctx exists, values extracted by registered context extractors will be added into result as `ctx.{name}`
 func Map(err error) map[string]any {
-	var kvs []kv
-	for err != nil {
-		uv, ok := err.(interface {
-			UnwrapAll() (any, any, error)
-		})
-		if !ok {
-			break
-		}
-		var k, v any
-		k, v, err = uv.UnwrapAll()
-		if ksPtr, ok := k.(*string); ok {
-			kvs = append(kvs, kv{*ksPtr, v})
-		}
-	}
+	kvs := appendKVs(nil, err, 0)
 	var m = make(map[string]any, len(kvs)+5) // NOTE: 5 means flatten meta(4)+status(1) in most common scenarios
-	for _, kv := range reverse(kvs) {
-		m[kv.k] = kv.v
-		switch me := kv.v.(type) {
+	mergeMap(m, kvs)
+	return m
+}
+
+// mergeMap merges sibling kvs from the oldest to the latest, the nested kvs of a node are merged right after the node
+func mergeMap(m map[string]any, kvs []kv) {
+	end := len(kvs)
+	for i := len(kvs) - 1; i >= 0; i-- {
+		if kvs[i].depth != kvs[0].depth {
+			continue
+		}
+		m[kvs[i].k] = kvs[i].v
+		switch me := kvs[i].v.(type) {
 		case *Meta:
 			m[MetaAttrAppFieldName] = me.app()
 			m[MetaAttrSourceFieldName] = me.source
 			m[MetaAttrCodeFieldName] = me.code
 			m[MetaAttrMessageFieldName] = me.msg
-		case error:
-			mm := Map(me)
-			for kk, vv := range mm {
-				m[kk] = vv
+		case context.Context:
+			for kk, vv := range ExtractContext(me) {
+				m[CtxAttrFieldPrefix+kk] = vv
 			}
 		}
+		if i+1 < end {
+			mergeMap(m, kvs[i+1:end])
+		}
+		end = i
 	}
-	return m
+}
+
+// appendKVs appends nodes of err with *string keys in walk order, i.e. from the latest to the oldest, nested nodes
+// follow their parent
+func appendKVs(kvs []kv, err error, depth int) []kv {
+	walk(err, depth, nil, func(n Node) WalkAction {
+		ksPtr, ok := n.Key.(*string)
+		if !ok {
+			return WalkSkipChildren
+		}
+		kvs = append(kvs, kv{*ksPtr, n.Value, n.Depth})
+		return WalkContinue
+	})
+	return kvs
 }
 
 type kv struct {
-	k string
-	v any
+	k     string
+	v     any
+	depth int
 }
 
 // Cause returns the underlying cause of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// An error value has a cause if it implements one of the following
+// interfaces(`Cause` is preferred, which is the interface of `github.com/pkg/errors`):
+//
+//     type causer interface {
+//            Cause() error
+//     }
 //
 //     type unwrap interface {
 //            Unwrap() error
 //     }
 //
-// If the error does not implement unwrap, the original error will
+// If the error does not implement either, or the next error is nil, the error itself will
 // be returned. If the error is nil, nil will be returned without further
-// investigation.
+// investigation. Multi errors(e.g. github.com/hashicorp/go-multierror) have no single cause, so they
+// are returned as is, see `Import` for their children.
 func Cause(err error) error {
 	for err != nil {
-		u, ok := err.(interface {
-			Unwrap() error
-		})
-		if !ok {
+		var next error
+		switch e := err.(type) {
+		case interface{ WrappedErrors() []error }, interface{ Errors() []error }, interface{ Unwrap() []error }:
+			// NOTE: no single cause
+		case interface{ Cause() error }:
+			next = e.Cause()
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		}
+		if next == nil {
 			break
 		}
-		err = u.Unwrap()
+		err = next
 	}
 	return err
 }
@@ -503,13 +551,21 @@ const (
 
 	// Default format value meta as `meta.String():key=*`
 	NoValue
+
+	// Tree format value meta as Default, but `%+v` renders as `FormatTree`
+	Tree
+
+	// PkgErrors format as `github.com/pkg/errors`: messages read outermost-first as `msg2: msg1: cause`, other attrs
+	// are omitted, and `%+v` prints the pkg/errors compatible stack output
+	PkgErrors
 )
 
-// SetFormatMode set mode for formatting the `valueError`
+// SetFormatMode set mode for formatting the `valueError`, mode should be builtin or registered by `RegisterFormatMode`,
+// otherwise `Default` is used
 func SetFormatMode(mode FormatMode) {
 	formatModeLock.Lock()
 	defer formatModeLock.Unlock()
-	if mode != Default && mode != Simplified && mode != NoValue {
+	if _, ok := formatModes[mode]; !ok && mode != Default && mode != Simplified && mode != NoValue && mode != Tree && mode != PkgErrors {
 		mode = Default
 	}
 	formatMode = mode
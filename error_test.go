@@ -28,7 +28,7 @@ func TestError(t *testing.T) {
 	var kkk string
 	ctx := context.WithValue(context.TODO(), &kkk, "vvv")
 	err = errors.CtxAttr.With(err, ctx)
-	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={context.TODO.WithValue(type *string, val vvv)}", err.Error(), "err chain")
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={}", err.Error(), "err chain")
 	assert.Truef(t, errors.Is(err, originErr), "err is originErr")
 	assert.Truef(t, errors.Is(err, errors.NotFound), "err is notfound")
 	assert.Truef(t, !errors.Is(err, errors.AlreadyExists), "err is not alreadyexists")
@@ -44,7 +44,7 @@ func TestError(t *testing.T) {
 	assert.Equalf(t, 404, errors.StatusAttr.Get(err), "err status")
 	assert.Equalf(t, "xxx", errors.Cause(err).Error(), "err cause")
 	err = errors.WithError(err, errors.AlreadyExists)
-	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={context.TODO.WithValue(type *string, val vvv)}:error={meta={source=errors;code=already_exists(6)}:status={409}}", err.Error(), "err with alreadyexists")
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={}:error={meta={source=errors;code=already_exists(6)}:status={409}}", err.Error(), "err with alreadyexists")
 	assert.Truef(t, errors.Is(err, originErr), "err is originErr")
 	assert.Truef(t, errors.Is(err, errors.NotFound), "err is notfound") // NOTE: also true
 	assert.Truef(t, errors.Is(err, errors.AlreadyExists), "err is not alreadyexists")
@@ -59,7 +59,7 @@ func TestError(t *testing.T) {
 	assert.Truef(t, errors.Get(err, errors.ErrorAttr.Key()) == errors.AlreadyExists, "get err is alreadyexists")
 	assert.Truef(t, errors.Get(err, errors.ErrorAttr.Key()) != errors.NotFound, "get err is not notfound")
 	err = errors.Adapt(err, errors.FailedPrecondition)
-	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={context.TODO.WithValue(type *string, val vvv)}:error={meta={source=errors;code=already_exists(6)}:status={409}}:caller={errors_test.TestError:61}", err.Error(), "err with alreadyexists")
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={wrapper}:caller={TestError}:ctx={}:error={meta={source=errors;code=already_exists(6)}:status={409}}:caller={errors_test.TestError:61}", err.Error(), "err with alreadyexists")
 	assert.Truef(t, errors.Is(err, originErr), "err is originErr after with FailedPrecondition")
 	assert.Truef(t, errors.Is(err, errors.NotFound), "err is notfound after with FailedPrecondition") // NOTE: also true
 	assert.Truef(t, errors.Is(err, errors.AlreadyExists), "err is not alreadyexists after with FailedPrecondition")
@@ -0,0 +1,115 @@
+// Package errorstest provides assertion helpers for errors built by package errors, failures print the whole error
+// chain as a tree, the helpers only depend on `testing.TB`.
+//
+// Usage:
+//
+//     err := errors.Adapt(errors.New("xxx"), errors.NotFound)
+//     errorstest.AssertMeta(t, err, errors.NotFound)
+//     errorstest.AssertStatus(t, err, 404)
+//     errorstest.AssertAttr(t, err, errors.SeverityAttr, errors.SeverityInfo)
+//     errorstest.AssertChain(t, err, "error=not_found(5)", "caller")
+package errorstest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+)
+
+// AssertMeta asserts the latest meta of err is the meta of want
+func AssertMeta(t testing.TB, err error, want errors.MetaError) bool {
+	t.Helper()
+	got := errors.MetaAttr.Get(err)
+	wantMeta := errors.MetaAttr.Get(want)
+	if got == wantMeta || (got != nil && wantMeta != nil && got.ID() == wantMeta.ID()) {
+		return true
+	}
+	return fail(t, "AssertMeta", err, metaString(wantMeta), metaString(got))
+}
+
+// AssertStatus asserts the status(see `errors.StatusAttr`) of err is want
+func AssertStatus(t testing.TB, err error, want int) bool {
+	t.Helper()
+	if got := errors.StatusAttr.Get(err); got != want {
+		return fail(t, "AssertStatus", err, want, got)
+	}
+	return true
+}
+
+// AssertAttr asserts the value of attr in err deeply equals to want
+func AssertAttr[T any](t testing.TB, err error, attr *errors.Attr[T], want T) bool {
+	t.Helper()
+	if got := attr.Get(err); !reflect.DeepEqual(got, want) {
+		return fail(t, "AssertAttr("+attr.Name()+")", err, want, got)
+	}
+	return true
+}
+
+// AssertChain asserts the top level attrs of err(from the oldest to the latest) contain pattern in order, each pattern
+// element is `name` or `name=value`, value matches the code if the attr value is a meta or meta error, otherwise
+// matches `fmt.Sprint(value)`
+func AssertChain(t testing.TB, err error, pattern ...string) bool {
+	t.Helper()
+	var layers []string
+	errors.Walk(err, func(n errors.Node) errors.WalkAction {
+		layers = append([]string{keyString(n.Key) + "=" + chainValue(n.Value)}, layers...)
+		return errors.WalkSkipChildren
+	})
+	i := 0
+	for _, l := range layers {
+		if i < len(pattern) && matchLayer(pattern[i], l) {
+			i++
+		}
+	}
+	if i == len(pattern) {
+		return true
+	}
+	return fail(t, "AssertChain", err, strings.Join(pattern, " > "), strings.Join(layers, " > "))
+}
+
+func matchLayer(pattern, layer string) bool {
+	if !strings.Contains(pattern, "=") {
+		return strings.SplitN(layer, "=", 2)[0] == pattern
+	}
+	return pattern == layer
+}
+
+func chainValue(v any) string {
+	switch tv := v.(type) {
+	case *errors.Meta:
+		return tv.Code()
+	case errors.MetaError:
+		if code := tv.Code(); code != "" {
+			return code
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func keyString(key any) string {
+	if sp, ok := key.(*string); ok {
+		return *sp
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// Tree renders the whole chain of err as a readable tree, see `errors.FormatTree`
+func Tree(err error) string {
+	return errors.FormatTree(err)
+}
+
+func metaString(m *errors.Meta) any {
+	if m == nil {
+		return nil
+	}
+	return m.ID()
+}
+
+func fail(t testing.TB, name string, err error, want, got any) bool {
+	t.Helper()
+	t.Errorf("%s failed:\n--- want\n+++ got\n- %v\n+ %v\nerror chain:\n%s\n", name, want, got, Tree(err))
+	return false
+}
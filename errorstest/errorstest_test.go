@@ -0,0 +1,79 @@
+package errorstest_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/ccmonky/errors/errorstest"
+	"github.com/ccmonky/inithook"
+)
+
+func init() {
+	err := inithook.ExecuteAttrSetters(context.Background(), inithook.AppName, "myapp")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// recorder records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	err := errors.WithError(errors.WithStack(errors.New("xxx")), errors.NotFound)
+	err = errors.With(err, errors.MessageOption("user not found"))
+
+	r := &recorder{}
+	if !errorstest.AssertMeta(r, err, errors.NotFound) ||
+		!errorstest.AssertStatus(r, err, 404) ||
+		!errorstest.AssertAttr(r, err, errors.SeverityAttr, errors.SeverityInfo) ||
+		!errorstest.AssertAttr(r, err, errors.MessageAttr, "user not found") ||
+		!errorstest.AssertChain(r, err, "stack", "error=not_found(5)", "msg=user not found") ||
+		!errorstest.AssertChain(r, err, "error", "msg") ||
+		!errorstest.AssertChain(r, err) {
+		t.Fatalf("unexpected failures: %v", r.failures)
+	}
+
+	cases := []struct {
+		ok   bool
+		want string
+	}{
+		{errorstest.AssertMeta(r, err, errors.Unavailable), "- myapp:github.com/ccmonky/errors:unavailable(14)\n+ myapp:github.com/ccmonky/errors:not_found(5)\n"},
+		{errorstest.AssertMeta(r, errors.New("xxx"), errors.NotFound), "+ <nil>\n"},
+		{errorstest.AssertStatus(r, err, 503), "- 503\n+ 404\n"},
+		{errorstest.AssertAttr(r, err, errors.MessageAttr, "xxx"), "AssertAttr(msg) failed"},
+		{errorstest.AssertChain(r, err, "msg", "error"), "- msg > error\n+ stack="},
+	}
+	if len(r.failures) != len(cases) {
+		t.Fatalf("want %d failures, got %d", len(cases), len(r.failures))
+	}
+	for i, c := range cases {
+		if c.ok {
+			t.Errorf("case %d should fail", i)
+		}
+		if !strings.Contains(r.failures[i], c.want) {
+			t.Errorf("case %d: %q should contains %q", i, r.failures[i], c.want)
+		}
+		if !strings.Contains(r.failures[i], "error chain:\n*errors.fundamental(\"xxx\")\n") {
+			t.Errorf("case %d: %q should contains error chain", i, r.failures[i])
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	err := errors.WithError(errors.New("xxx"), errors.NotFound)
+	if got, want := errorstest.Tree(err), errors.FormatTree(err); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,195 @@
+package errorstest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ccmonky/errors"
+)
+
+// GoldenDir is the directory of golden files
+var GoldenDir = "testdata"
+
+const (
+	// UpdateFlagName is the flag to rewrite golden files, e.g. `go test ./... -errorstest.update`, it's namespaced to not
+	// conflict with the common `-update` flag declared by test packages
+	UpdateFlagName = "errorstest.update"
+
+	// UpdateEnv is the environment variable to rewrite golden files, e.g. `ERRORSTEST_UPDATE=1 go test ./...`, which
+	// also works for packages whose tests are not run by `go test`
+	UpdateEnv = "ERRORSTEST_UPDATE"
+)
+
+var update = flag.Bool(UpdateFlagName, false, "update golden files of errorstest")
+
+func updating() bool {
+	if *update {
+		return true
+	}
+	ok, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return ok
+}
+
+// RegisterSnapshotFormat registers a rendering format used by `AssertSnapshot`, the same name will be overwritten,
+// builtin formats are: `error`(Error()), `verbose`(%+v), `tree`(see `errors.FormatTree`), `json` and `http`(see
+// `errors.WriteHTTP`)
+func RegisterSnapshotFormat(name string, render func(error) string) {
+	snapshotFormatsLock.Lock()
+	defer snapshotFormatsLock.Unlock()
+	snapshotFormats[name] = render
+}
+
+// AssertSnapshot renders err in all registered formats, normalizes volatile content(see `Normalize`) and compares it
+// with the golden file `{GoldenDir}/{name}.golden`, the golden file will be rewritten if `-errorstest.update` or `ERRORSTEST_UPDATE` specified
+func AssertSnapshot(t testing.TB, name string, err error) bool {
+	t.Helper()
+	snapshotFormatsLock.RLock()
+	names := make([]string, 0, len(snapshotFormats))
+	for n := range snapshotFormats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, n := range names {
+		fmt.Fprintf(&sb, "=== %s ===\n%s\n", n, strings.TrimSuffix(snapshotFormats[n](err), "\n"))
+	}
+	snapshotFormatsLock.RUnlock()
+	return AssertGolden(t, name, sb.String())
+}
+
+// AssertGolden normalizes got(see `Normalize`) and compares it with the golden file `{GoldenDir}/{name}.golden`, the
+// golden file will be rewritten if `-errorstest.update` or `ERRORSTEST_UPDATE` specified
+func AssertGolden(t testing.TB, name, got string) bool {
+	t.Helper()
+	got = Normalize(got)
+	path := filepath.Join(GoldenDir, name+".golden")
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("AssertGolden: create golden dir failed: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Errorf("AssertGolden: write golden file failed: %v", err)
+			return false
+		}
+		return true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("AssertGolden: read golden file failed(run with -%s to create it): %v", UpdateFlagName, err)
+		return false
+	}
+	if want := string(data); want != got {
+		t.Errorf("AssertGolden(%s) failed(run with -%s to accept):\n%s", path, UpdateFlagName, diffLines(want, got))
+		return false
+	}
+	return true
+}
+
+// Normalize replaces volatile content: drops runtime & testing stack frames, trims file paths of stack frames to base
+// names, replaces line numbers with `LINE`, addresses with `0xADDR` and program counters of stack in json with `"PCS"`
+func Normalize(s string) string {
+	s = stackJSONRegexp.ReplaceAllString(s, `$1"PCS"`)
+	s = volatileFrameRegexp.ReplaceAllString(s, "")
+	s = framePathRegexp.ReplaceAllString(s, "\t$1:LINE")
+	s = fileLineRegexp.ReplaceAllString(s, "$1:LINE")
+	s = callerLineRegexp.ReplaceAllString(s, "$1:LINE")
+	return addressRegexp.ReplaceAllString(s, "0xADDR")
+}
+
+var (
+	stackJSONRegexp     = regexp.MustCompile(`("key":\s*"stack",\s*"value":\s*)\[[\d,\s]*\]`)
+	volatileFrameRegexp = regexp.MustCompile(`\n(runtime|testing)\.[^\n]*\n\t[^\n]*`)
+	framePathRegexp     = regexp.MustCompile(`(?m)^\t\S*/([^/\s]+\.go):\d+`)
+	fileLineRegexp      = regexp.MustCompile(`(\w+\.go):\d+`)
+	callerLineRegexp    = regexp.MustCompile(`(\w+\.\w+):\d+\b`)
+	addressRegexp       = regexp.MustCompile(`0x[0-9a-f]+`)
+)
+
+// diffLines returns a line-oriented diff, lines only in want prefixed with `-` and lines only in got prefixed with `+`
+func diffLines(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// NOTE: longest common subsequence, golden files are small
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("--- want\n+++ got\n")
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func renderError(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func renderVerbose(err error) string {
+	return fmt.Sprintf("%+v", err)
+}
+
+func renderTree(err error) string {
+	return errors.FormatTree(err)
+}
+
+func renderJSON(err error) string {
+	data, merr := json.MarshalIndent(err, "", "    ")
+	if merr != nil {
+		return "marshal failed: " + merr.Error()
+	}
+	return string(data)
+}
+
+func renderHTTP(err error) string {
+	w := httptest.NewRecorder()
+	errors.WriteHTTP(w, err)
+	return fmt.Sprintf("%d\nContent-Type: %s\n\n%s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+}
+
+var (
+	snapshotFormats = map[string]func(error) string{
+		"error":   renderError,
+		"verbose": renderVerbose,
+		"tree":    renderTree,
+		"json":    renderJSON,
+		"http":    renderHTTP,
+	}
+	snapshotFormatsLock sync.RWMutex
+)
@@ -0,0 +1,76 @@
+package errorstest_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/ccmonky/errors/errorstest"
+)
+
+// NOTE: the common update flag of test packages must not conflict with errorstest
+var update = flag.Bool("update", false, "update golden files")
+
+func TestAssertSnapshot(t *testing.T) {
+	err := errors.WithError(errors.WithStack(errors.New("xxx")), errors.NotFound)
+	err = errors.With(err, errors.MessageOption("user not found"), errors.CallerAttr.Option("errorstest_test.TestAssertSnapshot:16"))
+	errorstest.AssertSnapshot(t, "not_found", err)
+	errorstest.AssertSnapshot(t, "nil", nil)
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir := errorstest.GoldenDir
+	errorstest.GoldenDir = t.TempDir()
+	defer func() { errorstest.GoldenDir = dir }()
+
+	r := &recorder{}
+	if errorstest.AssertGolden(r, "missing", "xxx") || !strings.Contains(r.failures[0], "-errorstest.update to create it") {
+		t.Fatalf("missing golden file should fail: %v", r.failures)
+	}
+
+	flag.Set(errorstest.UpdateFlagName, "true")
+	ok := errorstest.AssertGolden(r, "sub/attrs", "caller:0xc000110ed0\na\nb\n")
+	flag.Set(errorstest.UpdateFlagName, "false")
+	if !ok {
+		t.Fatalf("update failed: %v", r.failures)
+	}
+	t.Setenv(errorstest.UpdateEnv, "1")
+	ok = errorstest.AssertGolden(r, "env", "env")
+	os.Unsetenv(errorstest.UpdateEnv)
+	if !ok || errorstest.AssertGolden(r, "env", "changed") {
+		t.Fatalf("update by env failed: %v", r.failures)
+	}
+	r.failures = r.failures[:1]
+	data, _ := os.ReadFile(filepath.Join(errorstest.GoldenDir, "sub", "attrs.golden"))
+	if string(data) != "caller:0xADDR\na\nb\n" {
+		t.Fatalf("golden file should be normalized, got %q", data)
+	}
+	if !errorstest.AssertGolden(r, "sub/attrs", "caller:0xc000aaaaaa\na\nb\n") {
+		t.Fatalf("addresses should be normalized: %v", r.failures)
+	}
+	if errorstest.AssertGolden(r, "sub/attrs", "caller:0xc000aaaaaa\nb\nc\n") {
+		t.Fatal("should fail")
+	}
+	want := "--- want\n+++ got\n  caller:0xADDR\n- a\n  b\n+ c\n  \n"
+	if !strings.HasSuffix(r.failures[1], want) {
+		t.Fatalf("want diff %q, got %q", want, r.failures[1])
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"xxx\ngithub.com/a/b.F\n\t/home/u/b/b.go:12\nruntime.goexit\n\t/usr/local/go/src/runtime/asm_amd64.s:1598": "xxx\ngithub.com/a/b.F\n\tb.go:LINE",
+		"caller={b_test.TestX:42}":                  "caller={b_test.TestX:LINE}",
+		"TestX(x_test.go:4) +2 frames":              "TestX(x_test.go:LINE) +2 frames",
+		`{"caller:0xc000110ed0":{"type":"int"}}`:    `{"caller:0xADDR":{"type":"int"}}`,
+		`{"key":"stack","value":[6675231,5350698]}`: `{"key":"stack","value":"PCS"}`,
+		"status={404}":                              "status={404}",
+	} {
+		if got := errorstest.Normalize(in); got != want {
+			t.Errorf("Normalize(%q): want %q, got %q", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// FingerprintAttr used to attach fingerprint on error(see `WithFingerprint`), `FingerprintAttr.Get` computes
+// the fingerprint by `Fingerprint` with default options if not attached
+var FingerprintAttr = NewAttr[string]("fingerprint",
+	WithAttrDefault(func(err error) any {
+		if err == nil {
+			return ""
+		}
+		return Fingerprint(err)
+	}),
+	WithAttrDescription("grouping fingerprint as an attr"))
+
+// FingerprintOptions `Fingerprint` options
+type FingerprintOptions struct {
+	// Frames number of top stack frames used, default to 5
+	Frames int
+
+	// IgnoreLines ignore line numbers of stack frames, so that fingerprint is stable across releases
+	IgnoreLines bool
+
+	// Message use the normalized cause message(numbers, hex, uuids and quoted parameters are replaced) as well
+	Message bool
+}
+
+// FingerprintOption `Fingerprint` option
+type FingerprintOption func(*FingerprintOptions)
+
+// FingerprintFrames specify the number of top stack frames used
+func FingerprintFrames(n int) FingerprintOption {
+	return func(options *FingerprintOptions) {
+		options.Frames = n
+	}
+}
+
+// FingerprintIgnoreLines ignore line numbers of stack frames
+func FingerprintIgnoreLines() FingerprintOption {
+	return func(options *FingerprintOptions) {
+		options.IgnoreLines = true
+	}
+}
+
+// FingerprintMessage use the normalized cause message as well
+func FingerprintMessage() FingerprintOption {
+	return func(options *FingerprintOptions) {
+		options.Message = true
+	}
+}
+
+// Fingerprint returns a stable grouping key of err which computed from the latest meta id, cause type and the top
+// frames of the earliest stack in the chain, so that identical failures from different requests collapse together
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+	options := FingerprintOptions{Frames: 5}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var parts []string
+	if m := MetaAttr.Get(err); m != nil {
+		parts = append(parts, m.ID())
+	} else {
+		parts = append(parts, "")
+	}
+	cause := Cause(err)
+	parts = append(parts, reflect.TypeOf(cause).String())
+	if options.Message {
+		parts = append(parts, normalizeMessage(cause.Error()))
+	}
+	if st := originStack(err); st != nil {
+		frames := runtime.CallersFrames(*st)
+		for i := 0; i < options.Frames; i++ {
+			frame, more := frames.Next()
+			if frame.Function == "" && !more {
+				break
+			}
+			if options.IgnoreLines {
+				parts = append(parts, frame.Function)
+			} else {
+				parts = append(parts, frame.Function+":"+strconv.Itoa(frame.Line))
+			}
+			if !more {
+				break
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// WithFingerprint attach the fingerprint computed by `Fingerprint` on err, so that it will be in `Map` and json
+func WithFingerprint(err error, opts ...FingerprintOption) error {
+	if err == nil {
+		return nil
+	}
+	return FingerprintAttr.With(err, Fingerprint(err, opts...))
+}
+
+// normalizeMessage replace the parameters in message with placeholders
+func normalizeMessage(msg string) string {
+	for _, r := range messageParamReplacers {
+		msg = r.re.ReplaceAllString(msg, r.placeholder)
+	}
+	return msg
+}
+
+var messageParamReplacers = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), "<str>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<num>"},
+}
@@ -0,0 +1,50 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func failOrder(id int) error {
+	return errors.WithError(errors.Wrap(fmt.Errorf("order %d not found", id), "load order"), errors.NotFound)
+}
+
+func failOrderElsewhere(id int) error {
+	return errors.WithError(errors.Wrap(fmt.Errorf("order %d not found", id), "load order"), errors.NotFound)
+}
+
+func TestFingerprint(t *testing.T) {
+	var errs []error
+	for id := 0; id < 3; id++ {
+		errs = append(errs, failOrder(id)) // NOTE: same call site, like different requests of the same handler
+	}
+	fp := errors.Fingerprint(errs[0])
+	assert.Equalf(t, 32, len(fp), "length")
+	assert.Equalf(t, fp, errors.Fingerprint(errs[1]), "same failure from different requests")
+	assert.Equalf(t, fp, errors.FingerprintAttr.Get(errs[2]), "attr default")
+	assert.NotEqualf(t, fp, errors.Fingerprint(errors.WithError(errs[0], errors.Unavailable)), "different meta")
+	assert.NotEqualf(t, fp, errors.Fingerprint(errors.WithError(errors.Wrap(io.EOF, "load order"), errors.NotFound)), "different cause type")
+	assert.NotEqualf(t, fp, errors.Fingerprint(failOrderElsewhere(0)), "different stack")
+	assert.Equalf(t, "", errors.Fingerprint(nil), "nil")
+
+	withMessage := errors.Fingerprint(errs[0], errors.FingerprintMessage())
+	assert.Equalf(t, withMessage, errors.Fingerprint(errs[1], errors.FingerprintMessage()), "message parameters ignored")
+	assert.NotEqualf(t, withMessage, fp, "message used")
+
+	c := errors.WithStack(io.EOF)
+	d := errors.WithStack(io.EOF)
+	assert.NotEqualf(t, errors.Fingerprint(c, errors.FingerprintFrames(1)), errors.Fingerprint(d, errors.FingerprintFrames(1)), "lines differ")
+	assert.Equalf(t, errors.Fingerprint(c, errors.FingerprintFrames(1), errors.FingerprintIgnoreLines()), errors.Fingerprint(d, errors.FingerprintFrames(1), errors.FingerprintIgnoreLines()), "lines ignored")
+
+	err := errors.WithFingerprint(errs[0])
+	assert.Equalf(t, fp, errors.Map(err)["fingerprint"], "map")
+	data, jerr := json.Marshal(err)
+	assert.Nilf(t, jerr, "marshal")
+	assert.Truef(t, strings.Contains(string(data), `"key":"fingerprint","value":"`+fp+`"`), "json: %s", data)
+}
@@ -0,0 +1,194 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// FormatModeAttr specify the format mode of a single error, it overrides the global mode(see `SetFormatMode`) and is
+// hidden from the formatted string
+var FormatModeAttr = NewAttr[FormatMode]("format_mode", WithAttrDescription("format mode of the error"))
+
+// FormatAttr is an attr layer passed to `AttrFormatter`
+type FormatAttr struct {
+	// Inner is the formatted string of the inner chain, empty if the inner is the empty error
+	Inner string
+
+	// Name is the attr name, or `fmt.Sprint(key)` if the key type is not *string
+	Name string
+
+	// Value is the attr value
+	Value any
+
+	// Text is the formatted value, nested error chains are formatted with the same mode
+	Text string
+}
+
+// AttrFormatter formats an attr layer onto the inner string
+type AttrFormatter func(FormatAttr) string
+
+// HiddenAttr is an `AttrFormatter` which hides the attr
+func HiddenAttr(a FormatAttr) string {
+	return a.Inner
+}
+
+// DefaultAttr is an `AttrFormatter` which formats the attr as `inner:name={text}`
+func DefaultAttr(a FormatAttr) string {
+	if a.Inner == "" {
+		return a.Name + "={" + a.Text + "}"
+	}
+	return a.Inner + ":" + a.Name + "={" + a.Text + "}"
+}
+
+// AttrTemplate creates an `AttrFormatter` from text/template with `FormatAttr` as data, panics if text is invalid,
+// e.g. `{{.Value}}: {{.Inner}}`
+func AttrTemplate(text string) AttrFormatter {
+	tmpl := template.Must(template.New("attr").Parse(text))
+	return func(a FormatAttr) string {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, a); err != nil {
+			return DefaultAttr(a)
+		}
+		return sb.String()
+	}
+}
+
+// FormatModeConfig defines a user format mode
+type FormatModeConfig struct {
+	// Attrs formatters by attr name
+	Attrs map[string]AttrFormatter
+
+	// Default formatter for attrs not in Attrs, default to `DefaultAttr`
+	Default AttrFormatter
+}
+
+// RegisterFormatMode registers a user format mode, which can be selected globally by `SetFormatMode` or per error by
+// `FormatModeAttr`
+//
+// Usage:
+//
+//     human := errors.RegisterFormatMode(errors.FormatModeConfig{
+//         Attrs: map[string]errors.AttrFormatter{
+//             "meta":  errors.AttrTemplate("[{{.Value.Code}}] {{.Value.Message}}"),
+//             "msg":   errors.AttrTemplate("{{.Value}}: {{.Inner}}"),
+//             "error": errors.AttrTemplate("{{.Inner}}: {{.Text}}"),
+//         },
+//         Default: errors.HiddenAttr,
+//     })
+//     errors.SetFormatMode(human)
+//     // or errors.With(err, errors.FormatModeAttr.Option(human))
+func RegisterFormatMode(config FormatModeConfig) FormatMode {
+	if config.Default == nil {
+		config.Default = DefaultAttr
+	}
+	formatModeLock.Lock()
+	defer formatModeLock.Unlock()
+	mode := nextFormatMode
+	nextFormatMode++
+	formatModes[mode] = &config
+	return mode
+}
+
+// chainFormatMode determines the format mode of err once for the whole chain
+func chainFormatMode(err error) FormatMode {
+	mode, found := Default, false
+	walk(err, 0, nil, func(n Node) WalkAction {
+		if n.Key == FormatModeAttr.Key() {
+			mode, found = n.Value.(FormatMode)
+			return WalkStop
+		}
+		return WalkSkipChildren
+	})
+	if found {
+		return mode
+	}
+	formatModeLock.RLock()
+	defer formatModeLock.RUnlock()
+	return formatMode
+}
+
+// formatChain formats err with mode, the base of the chain(see `chainBase`) first and then attrs from the oldest to the
+// latest
+func formatChain(err error, mode FormatMode) string {
+	var nodes []Node
+	walk(err, 0, nil, func(n Node) WalkAction {
+		nodes = append(nodes, n)
+		return WalkSkipChildren
+	})
+	formatModeLock.RLock()
+	config := formatModes[mode]
+	formatModeLock.RUnlock()
+	var s string
+	hasInner := false
+	if base := chainBase(err); base != empty && base != nil {
+		s, hasInner = base.Error(), true
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		k, v := nodes[i].Key, nodes[i].Value
+		if k == FormatModeAttr.Key() {
+			continue
+		}
+		name, ok := k.(*string)
+		if config == nil { // NOTE: builtin modes
+			inner := ""
+			if hasInner {
+				inner = s + ":"
+			}
+			switch mode {
+			case Simplified:
+				s = inner + "*={*}"
+			case NoValue:
+				if ok {
+					s = inner + *name + "={*}"
+				} else {
+					s = inner + fmt.Sprintf("%v={*}", k)
+				}
+			default:
+				if ok {
+					s = inner + fmt.Sprintf("%s={%v}", *name, formatValue(v, mode))
+				} else {
+					s = inner + fmt.Sprintf("%v={%v}", k, formatValue(v, mode))
+				}
+			}
+			hasInner = true
+			continue
+		}
+		a := FormatAttr{
+			Inner: s,
+			Value: v,
+			Text:  fmt.Sprintf("%v", formatValue(v, mode)),
+		}
+		if ok {
+			a.Name = *name
+		} else {
+			a.Name = fmt.Sprintf("%v", k)
+		}
+		formatter := config.Attrs[a.Name]
+		if formatter == nil {
+			formatter = config.Default
+		}
+		s = formatter(a)
+	}
+	return s
+}
+
+// formatValue returns the value used for formatting, nested error chains are formatted with the same mode
+func formatValue(v any, mode FormatMode) any {
+	if isChain(v) {
+		return formatChain(v.(error), mode)
+	}
+	return displayValue(v)
+}
+
+var (
+	formatModes = map[FormatMode]*FormatModeConfig{
+		PkgErrors: {
+			Attrs: map[string]AttrFormatter{
+				"msg": pkgErrorsMessage,
+			},
+			Default: HiddenAttr,
+		},
+	}
+	nextFormatMode = PkgErrors + 1
+)
@@ -0,0 +1,84 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinFormatModes(t *testing.T) {
+	err := errors.WithMessage(errors.WithError(errors.New("xxx"), errors.NotFound), "msg")
+	defer errors.SetFormatMode(errors.Default)
+	for mode, want := range map[errors.FormatMode]string{
+		errors.Default:    "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={msg}",
+		errors.Simplified: "xxx:*={*}:*={*}",
+		errors.NoValue:    "xxx:error={*}:msg={*}",
+		errors.Tree:       "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={msg}",
+	} {
+		errors.SetFormatMode(mode)
+		assert.Equalf(t, want, err.Error(), "mode %d", mode)
+	}
+	errors.SetFormatMode(errors.FormatMode(-1))
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:msg={msg}", err.Error(), "unknown mode as default")
+}
+
+func TestRegisterFormatMode(t *testing.T) {
+	human := errors.RegisterFormatMode(errors.FormatModeConfig{
+		Attrs: map[string]errors.AttrFormatter{
+			"meta":  errors.AttrTemplate("[{{.Value.Code}}] {{.Value.Message}}"),
+			"msg":   errors.AttrTemplate("{{.Value}}: {{.Inner}}"),
+			"error": errors.AttrTemplate("{{.Inner}}: {{.Text}}"),
+		},
+		Default: errors.HiddenAttr,
+	})
+	ctx := context.WithValue(context.Background(), ctxKey("k"), "v")
+	err := errors.WithError(errors.New("xxx"), errors.NotFound)
+	err = errors.With(err, errors.CtxAttr.Option(ctx), errors.StatusAttr.Option(400))
+	err = errors.WithMessage(err, "load user")
+
+	errors.SetFormatMode(human)
+	assert.Equal(t, "load user: xxx: [not_found(5)] not found", err.Error())
+	assert.Equal(t, "load user: xxx: [not_found(5)] not found", fmt.Sprint(err))
+	errors.SetFormatMode(errors.Default)
+	assert.Equal(t, "xxx:error={meta={source=errors;code=not_found(5)}:status={404}}:ctx={}:status={400}:msg={load user}", err.Error())
+
+	perError := errors.With(err, errors.FormatModeAttr.Option(human))
+	assert.Equalf(t, "load user: xxx: [not_found(5)] not found", perError.Error(), "per error mode, and the mode attr is hidden")
+	assert.Equalf(t, human, errors.FormatModeAttr.Get(perError), "mode attr")
+
+	simplified := errors.With(err, errors.FormatModeAttr.Option(errors.NoValue))
+	assert.Equal(t, "xxx:error={*}:ctx={*}:status={*}:msg={*}", simplified.Error())
+
+	keep := errors.RegisterFormatMode(errors.FormatModeConfig{
+		Attrs: map[string]errors.AttrFormatter{"severity": errors.HiddenAttr, "status": errors.HiddenAttr},
+	})
+	assert.Equalf(t, "xxx:error={meta={source=errors;code=not_found(5)}}", errors.With(errors.WithError(errors.New("xxx"), errors.NotFound), errors.FormatModeAttr.Option(keep)).Error(), "default attr formatter")
+	assert.NotEqual(t, human, keep)
+}
+
+type wrapped struct {
+	error
+}
+
+func (w wrapped) Error() string { return "wrapped(" + w.error.Error() + ")" }
+func (w wrapped) Unwrap() error { return w.error }
+
+func TestFormatForeignWrapper(t *testing.T) {
+	err := errors.WithMessage(fmt.Errorf("read config: %w", io.EOF), "x")
+	assert.Equalf(t, "read config: EOF:msg={x}", err.Error(), "text of %%w wrapper kept")
+	assert.Equalf(t, io.EOF, errors.Cause(err), "cause unchanged")
+
+	err = errors.WithMessage(wrapped{errors.WithError(io.EOF, errors.NotFound)}, "outer")
+	assert.Equalf(t, "wrapped(EOF:error={meta={source=errors;code=not_found(5)}:status={404}}):msg={outer}", err.Error(), "text of foreign wrapper kept")
+
+	err = errors.WithError(errors.Errorf("load config: %w", io.EOF), errors.NotFound)
+	assert.Equalf(t, "load config: EOF:error={meta={source=errors;code=not_found(5)}:status={404}}", err.Error(), "text of Errorf kept")
+
+	err = errors.With(errors.WithMessage(fmt.Errorf("read config: %w", io.EOF), "x"), errors.FormatModeAttr.Option(errors.PkgErrors))
+	assert.Equalf(t, "x: read config: EOF", err.Error(), "pkg/errors mode")
+	assert.Equalf(t, "read config: EOF\nx", fmt.Sprintf("%+v", err), "pkg/errors mode verbose")
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TaskAttr used to attach the label of task on the error returned by `Group`
+var TaskAttr = NewAttr[string]("task", WithAttrDescription("label of group task as an attr"))
+
+// GroupPolicy picks the index of errs whose meta will be used as the aggregate(latest) meta of `Group.Wait`, errs are
+// in the order of `Group.Go` instead of the order of completion, so that the result is deterministic, an index out of
+// range is treated as 0
+type GroupPolicy func(errs []error) int
+
+// FirstError picks the error of the first failed task in the order of `Group.Go`(not the earliest failed one)
+func FirstError(errs []error) int {
+	return 0
+}
+
+// HighestStatus picks the error with highest `StatusAttr`, the first one wins if equal
+func HighestStatus(errs []error) int {
+	idx, max := 0, 0
+	for i, err := range errs {
+		if status := StatusAttr.Get(err); status > max {
+			idx, max = i, status
+		}
+	}
+	return idx
+}
+
+// MostSevere picks the error with the highest `MaxSeverity`, the first one wins if equal
+func MostSevere(errs []error) int {
+	idx, max := 0, SeverityDebug-1
+	for i, err := range errs {
+		if severity := MaxSeverity(err); severity > max {
+			idx, max = i, severity
+		}
+	}
+	return idx
+}
+
+// Group runs tasks in goroutines and collects all errors, unlike `golang.org/x/sync/errgroup` which only returns
+// the first error, the zero value is valid and uses `FirstError` policy
+//
+// Usage:
+//
+//     g, ctx := errors.GroupWithContext(ctx)
+//     g.Policy = errors.HighestStatus
+//     g.Go("users", func() error { return loadUsers(ctx) })
+//     g.Go("orders", func() error { return loadOrders(ctx) })
+//     err := g.Wait() // errors.GetAllErrors(err) contains all failures labeled by `TaskAttr`
+type Group struct {
+	// Policy picks the aggregate meta of `Wait`, default to `FirstError`
+	Policy GroupPolicy
+
+	// FailFast cancel the shared context on the first error, only works with `GroupWithContext`
+	FailFast bool
+
+	cancel func()
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+	total  int
+	errs   []taskError
+}
+
+// taskError is the error of the index-th task of `Group.Go`
+type taskError struct {
+	index int
+	err   error
+}
+
+// GroupWithContext returns a new Group and a derived context which is canceled when `Wait` returns,
+// or the first task fails if `FailFast`
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs fn in a new goroutine, panic will be recovered(see `Safe`), the returned error will be labeled by `TaskAttr`
+func (g *Group) Go(label string, fn func() error) {
+	g.lock.Lock()
+	index := g.total
+	g.total++
+	g.lock.Unlock()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := Safe(fn); err != nil {
+			g.lock.Lock()
+			g.errs = append(g.errs, taskError{index, TaskAttr.With(err, label)})
+			g.lock.Unlock()
+			if g.FailFast && g.cancel != nil {
+				g.cancel()
+			}
+		}
+	}()
+}
+
+// Wait blocks until all tasks return, then returns nil if all succeed, otherwise returns a combined error which
+// cause is `n of m tasks failed` and all failures are attached by `WithError` in the order of `Go`, the error picked by
+// `Policy` is attached last, so it's meta is the latest one
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	sort.Slice(g.errs, func(i, j int) bool {
+		return g.errs[i].index < g.errs[j].index
+	})
+	errs := make([]error, len(g.errs))
+	for i, te := range g.errs {
+		errs[i] = te.err
+	}
+	policy := g.Policy
+	if policy == nil {
+		policy = FirstError
+	}
+	picked := policy(errs)
+	if picked < 0 || picked >= len(errs) {
+		picked = 0
+	}
+	err := fmt.Errorf("%d of %d tasks failed", len(errs), g.total)
+	for i, e := range errs {
+		if i != picked {
+			err = WithError(err, e)
+		}
+	}
+	return WithError(err, errs[picked])
+}
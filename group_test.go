@@ -0,0 +1,101 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	var g errors.Group
+	g.Go("ok", func() error { return nil })
+	assert.Nilf(t, g.Wait(), "all succeed")
+
+	g = errors.Group{Policy: errors.HighestStatus}
+	g.Go("ok", func() error { return nil })
+	g.Go("not_found", func() error { return errors.WithError(io.EOF, errors.NotFound) })
+	g.Go("unavailable", func() error { return errors.Unavailable })
+	g.Go("panic", func() error { panic("boom") })
+	err := g.Wait()
+	assert.Equalf(t, "3 of 4 tasks failed", errors.Cause(err).Error(), "cause")
+	assert.Equalf(t, 6, len(errors.GetAllErrors(err)), "all errors(cause + 3 failures + 2 metas attached in failures)")
+	assert.Equalf(t, []string{"not_found", "panic", "unavailable"}, sorted(errors.TaskAttr.GetAll(err)), "labels")
+	var codes []string
+	for _, m := range errors.MetaAttr.GetAll(err) {
+		codes = append(codes, m.Code())
+	}
+	assert.Equalf(t, []string{"internal(13)", "not_found(5)", "unavailable(14)"}, sorted(codes), "all metas")
+	assert.Equalf(t, "unavailable(14)", errors.MetaAttr.Get(err).Code(), "highest status")
+	assert.Equalf(t, 503, errors.StatusAttr.Get(err), "status")
+}
+
+func TestGroupPolicy(t *testing.T) {
+	errs := []error{
+		errors.WithError(io.EOF, errors.NotFound),
+		errors.WithError(io.EOF, errors.Unavailable),
+		errors.WithError(io.EOF, errors.DataLoss),
+		errors.WithError(io.EOF, errors.Internal),
+	}
+	assert.Equalf(t, 0, errors.FirstError(errs), "first")
+	assert.Equalf(t, 1, errors.HighestStatus(errs), "highest status")
+	assert.Equalf(t, 2, errors.MostSevere(errs), "most severe")
+	assert.Equalf(t, 1, errors.MostSevere([]error{errors.NotFound, io.EOF}), "most severe without meta")
+}
+
+func TestGroupOrder(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var labels []string
+		g := errors.Group{Policy: func(errs []error) int {
+			for _, err := range errs {
+				labels = append(labels, errors.TaskAttr.Get(err))
+			}
+			return errors.FirstError(errs)
+		}}
+		release := make(chan struct{})
+		g.Go("ok", func() error { return nil })
+		g.Go("slow", func() error {
+			<-release
+			return errors.WithError(io.EOF, errors.NotFound)
+		})
+		g.Go("fast", func() error {
+			defer close(release)
+			return errors.WithError(io.EOF, errors.Unavailable)
+		})
+		err := g.Wait()
+		assert.Equalf(t, "not_found(5)", errors.MetaAttr.Get(err).Code(), "first task in the order of Go, not the earliest failed one")
+		assert.Equalf(t, []string{"slow", "fast"}, labels, "errs of policy in the order of Go")
+	}
+}
+
+func TestGroupPolicyOutOfRange(t *testing.T) {
+	for _, picked := range []int{5, -1} {
+		g := errors.Group{Policy: func(errs []error) int { return picked }}
+		g.Go("not_found", func() error { return errors.WithError(io.EOF, errors.NotFound) })
+		g.Go("ok", func() error { return nil })
+		var err error
+		assert.NotPanicsf(t, func() { err = g.Wait() }, "policy returns %d", picked)
+		assert.Equalf(t, "not_found(5)", errors.MetaAttr.Get(err).Code(), "policy returns %d: treated as 0", picked)
+	}
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := errors.GroupWithContext(context.TODO())
+	g.FailFast = true
+	g.Go("fail", func() error { return io.EOF })
+	g.Go("wait", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	err := g.Wait()
+	assert.Equalf(t, 2, len(errors.TaskAttr.GetAll(err)), "all failed")
+	assert.Equalf(t, context.Canceled, ctx.Err(), "ctx canceled")
+}
+
+func sorted(ss []string) []string {
+	sort.Strings(ss)
+	return ss
+}
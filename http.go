@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// MethodNotAllowed is `InvalidArgument` responded with status 405, returned by handlers of this package for the methods
+// they do not support
+var MethodNotAllowed = With(InvalidArgument, StatusOption(http.StatusMethodNotAllowed)).(MetaError)
+
+// HTTPBody returns the http response body of err, which contains the fields of the latest meta,
+// `Unknown` meta is used if err carries no meta, and `OK` meta is used if err == nil
+func HTTPBody(err error) map[string]any {
+	var m *Meta
+	if err == nil {
+		m = MetaAttr.Get(OK)
+	} else if m = MetaAttr.Get(err); m == nil {
+		m = MetaAttr.Get(Unknown)
+	}
+	return map[string]any{
+		MetaAttrAppFieldName:     m.app(),
+		MetaAttrSourceFieldName:  m.source,
+		MetaAttrCodeFieldName:    m.code,
+		MetaAttrMessageFieldName: m.msg,
+	}
+}
+
+// WriteHTTP write err into w as json response, the status code is `StatusAttr.Get(err)` and body is `HTTPBody(err)`,
+// `Retry-After` header is set if err carries `RetryAfterAttr`, non-nil err will be observed by the installed `Observer`
+func WriteHTTP(w http.ResponseWriter, err error) {
+	if err != nil {
+		observe(ObserveHTTP, err)
+	}
+	writeHTTP(w, err)
+}
+
+// writeHTTP is `WriteHTTP` without observing, used by the handlers which serve observed errors(e.g. `NewAdminHandler`),
+// so that their own failures are not recorded
+func writeHTTP(w http.ResponseWriter, err error) {
+	data, merr := json.Marshal(HTTPBody(err))
+	if merr != nil {
+		http.Error(w, merr.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if d := RetryAfterAttr.Get(err); d > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	w.WriteHeader(StatusAttr.Get(err))
+	w.Write(data)
+}
@@ -0,0 +1,30 @@
+package errors_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	w := httptest.NewRecorder()
+	errors.WriteHTTP(w, errors.WithMessage(errors.WithError(errors.New("xxx"), errors.NotFound), "wrapper"))
+	assert.Equalf(t, 404, w.Code, "not found status")
+	assert.Equalf(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), "content type")
+	assert.JSONEq(t, `{"meta.app":"myapp","meta.code":"not_found(5)","meta.message":"not found","meta.source":"github.com/ccmonky/errors"}`, w.Body.String(), "not found body")
+
+	w = httptest.NewRecorder()
+	errors.WriteHTTP(w, errors.New("xxx"))
+	assert.Equalf(t, 500, w.Code, "no meta status")
+	assert.Equalf(t, "unknown(2)", errors.HTTPBody(errors.New("xxx"))["meta.code"], "no meta body")
+	assert.Equalf(t, "success(0)", errors.HTTPBody(nil)["meta.code"], "nil body")
+	assert.Equalf(t, "", w.Header().Get("Retry-After"), "no retry after")
+
+	w = httptest.NewRecorder()
+	errors.WriteHTTP(w, errors.With(errors.WithError(errors.New("xxx"), errors.Unavailable), errors.RetryAfterAttr.Option(1500*time.Millisecond)))
+	assert.Equalf(t, 503, w.Code, "unavailable status")
+	assert.Equalf(t, "2", w.Header().Get("Retry-After"), "retry after seconds rounded up")
+}
@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RetryAfterAttr the duration after which the client can retry, `WriteHTTP` responds it as `Retry-After` header
+var RetryAfterAttr = NewAttr[time.Duration]("retry_after", WithAttrDescription("duration after which the client can retry"))
+
+// InjectPointAttr the name of the injection point which injects the error
+var InjectPointAttr = NewAttr[string]("inject_point", WithAttrDescription("injection point which injects the error"))
+
+// InjectTenantField is the name of context field(see `RegisterContextExtractor`) used to match `InjectRule.Tenants`
+var InjectTenantField = "tenant"
+
+// injectHandlerAllow is the `Allow` header responded by `InjectHandler` with status 405
+const injectHandlerAllow = "GET, POST, PUT, DELETE"
+
+// InjectRule defines when and what error to inject at an injection point(see `Inject`), fault injection is compiled to
+// no-op unless built with `-tags errors_inject`
+type InjectRule struct {
+	// Point is the name of injection point, e.g. `orders.db.save`
+	Point string
+
+	// Error is the MetaError to inject, default to `Unavailable`
+	Error MetaError
+
+	// RetryAfter attached as `RetryAfterAttr` if > 0
+	RetryAfter time.Duration
+
+	// Options extra options applied on the injected error
+	Options []Option
+
+	// Probability injects with the probability in (0, 1], 0 means always
+	Probability float64
+
+	// EveryN injects every nth call(after tenant matched) if > 0
+	EveryN int
+
+	// Tenants injects only if the tenant extracted from context is in it, empty means all tenants
+	Tenants []string
+}
+
+type injectRuleJSON struct {
+	Point       string   `json:"point"`
+	Error       string   `json:"error,omitempty"`
+	RetryAfter  string   `json:"retry_after,omitempty"`
+	Probability float64  `json:"probability,omitempty"`
+	EveryN      int      `json:"every_n,omitempty"`
+	Tenants     []string `json:"tenants,omitempty"`
+}
+
+// MarshalJSON marshal error as meta id and retry after as duration string
+func (r InjectRule) MarshalJSON() ([]byte, error) {
+	rj := injectRuleJSON{
+		Point:       r.Point,
+		Probability: r.Probability,
+		EveryN:      r.EveryN,
+		Tenants:     r.Tenants,
+	}
+	if m := MetaAttr.Get(r.Error); m != nil {
+		rj.Error = m.ID()
+	}
+	if r.RetryAfter > 0 {
+		rj.RetryAfter = r.RetryAfter.String()
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON unmarshal error from registered meta id and retry after from duration string
+func (r *InjectRule) UnmarshalJSON(data []byte) error {
+	var rj injectRuleJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return WithError(err, InvalidArgument)
+	}
+	rule := InjectRule{
+		Point:       rj.Point,
+		Probability: rj.Probability,
+		EveryN:      rj.EveryN,
+		Tenants:     rj.Tenants,
+	}
+	if rj.Error != "" {
+		me, ok := AllMetaErrors()[rj.Error]
+		if !ok {
+			return WithError(fmt.Errorf("meta error %s not found", rj.Error), InvalidArgument)
+		}
+		rule.Error = me
+	}
+	if rj.RetryAfter != "" {
+		d, err := time.ParseDuration(rj.RetryAfter)
+		if err != nil {
+			return WithError(err, InvalidArgument)
+		}
+		rule.RetryAfter = d
+	}
+	*r = rule
+	return nil
+}
+
+// injectError builds the error injected by rule at point
+func injectError(point string, rule *InjectRule) error {
+	me := rule.Error
+	if me == nil {
+		me = Unavailable
+	}
+	opts := []Option{InjectPointAttr.Option(point)}
+	if rule.RetryAfter > 0 {
+		opts = append(opts, RetryAfterAttr.Option(rule.RetryAfter))
+	}
+	opts = append(opts, rule.Options...)
+	return With(attachError(fmt.Errorf("fault injected at %s", point), me), opts...)
+}
+
+// InjectHandler returns an `http.Handler` to configure fault injection at runtime:
+//
+//     GET                  list all rules
+//     POST                 add a rule, e.g. `{"point":"orders.db.save","error":"<meta id>","retry_after":"1s","every_n":2}`
+//     DELETE ?point={name} remove rules of point, all rules will be removed if point not specified
+//
+// Usage:
+//
+//     http.Handle("/debug/inject", errors.InjectHandler())
+func InjectHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut:
+			var rule InjectRule
+			if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+				WriteHTTP(w, err)
+				return
+			}
+			if err := AddInjectRule(rule); err != nil {
+				WriteHTTP(w, err)
+				return
+			}
+		case http.MethodDelete:
+			RemoveInjectRules(r.URL.Query().Get("point"))
+		default:
+			w.Header().Set("Allow", injectHandlerAllow)
+			WriteHTTP(w, WithError(fmt.Errorf("method %s not allowed", r.Method), MethodNotAllowed))
+			return
+		}
+		rules := InjectRules()
+		if rules == nil {
+			rules = []InjectRule{}
+		}
+		writeAdminJSON(w, rules)
+	})
+}
@@ -0,0 +1,35 @@
+//go:build !errors_inject
+// +build !errors_inject
+
+package errors
+
+import (
+	"context"
+	"fmt"
+)
+
+// InjectEnabled reports whether fault injection is compiled in(build with `-tags errors_inject`)
+const InjectEnabled = false
+
+// Inject is no-op unless built with `-tags errors_inject`
+func Inject(point string) error {
+	return nil
+}
+
+// InjectCtx is no-op unless built with `-tags errors_inject`
+func InjectCtx(ctx context.Context, point string) error {
+	return nil
+}
+
+// AddInjectRule returns `Unimplemented` unless built with `-tags errors_inject`
+func AddInjectRule(rule InjectRule) error {
+	return WithError(fmt.Errorf("fault injection disabled, build with -tags errors_inject"), Unimplemented)
+}
+
+// RemoveInjectRules is no-op unless built with `-tags errors_inject`
+func RemoveInjectRules(point string) {}
+
+// InjectRules returns nil unless built with `-tags errors_inject`
+func InjectRules() []InjectRule {
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build !errors_inject
+// +build !errors_inject
+
+package errors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectDisabled(t *testing.T) {
+	assert.Falsef(t, errors.InjectEnabled, "inject disabled")
+	err := errors.AddInjectRule(errors.InjectRule{Point: "p"})
+	assert.Truef(t, errors.Is(err, errors.Unimplemented), "add rule unimplemented")
+	assert.Nilf(t, errors.Inject("p"), "inject nothing")
+	assert.Nilf(t, errors.InjectRules(), "no rules")
+
+	w := httptest.NewRecorder()
+	errors.InjectHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"point":"p"}`)))
+	assert.Equalf(t, http.StatusNotImplemented, w.Code, "handler unimplemented")
+}
@@ -0,0 +1,132 @@
+//go:build errors_inject
+// +build errors_inject
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// InjectEnabled reports whether fault injection is compiled in(build with `-tags errors_inject`)
+const InjectEnabled = true
+
+// Inject returns the error configured by the first matched rule of point, or nil if no rule matched
+//
+// Usage:
+//
+//     func (s *Store) Save(ctx context.Context, o *Order) error {
+//         if err := errors.InjectCtx(ctx, "orders.db.save"); err != nil {
+//             return err
+//         }
+//         ...
+//     }
+func Inject(point string) error {
+	return InjectCtx(context.Background(), point)
+}
+
+// InjectCtx is the same as `Inject`, but the tenant extracted from ctx(see `InjectTenantField`) is used to match rules
+func InjectCtx(ctx context.Context, point string) error {
+	injectLock.RLock()
+	rules := injectRules[point]
+	injectLock.RUnlock()
+	for _, rule := range rules {
+		if rule.match(ctx) {
+			return injectError(point, &rule.InjectRule)
+		}
+	}
+	return nil
+}
+
+// AddInjectRule adds rule, rules of the same point are matched in the order of adding
+func AddInjectRule(rule InjectRule) error {
+	if rule.Point == "" {
+		return WithError(fmt.Errorf("inject rule point is empty"), InvalidArgument)
+	}
+	if rule.Probability < 0 || rule.Probability > 1 {
+		return WithError(fmt.Errorf("inject rule probability %v not in [0, 1]", rule.Probability), InvalidArgument)
+	}
+	injectLock.Lock()
+	defer injectLock.Unlock()
+	injectRules[rule.Point] = append(injectRules[rule.Point][:len(injectRules[rule.Point]):len(injectRules[rule.Point])],
+		&injectRule{InjectRule: rule})
+	return nil
+}
+
+// RemoveInjectRules removes all rules of point, all rules will be removed if point is empty
+func RemoveInjectRules(point string) {
+	injectLock.Lock()
+	defer injectLock.Unlock()
+	if point == "" {
+		injectRules = make(map[string][]*injectRule)
+		return
+	}
+	delete(injectRules, point)
+}
+
+// InjectRules returns all rules
+func InjectRules() []InjectRule {
+	injectLock.RLock()
+	defer injectLock.RUnlock()
+	var rules []InjectRule
+	for _, rs := range injectRules {
+		for _, r := range rs {
+			rules = append(rules, r.InjectRule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Point < rules[j].Point
+	})
+	return rules
+}
+
+type injectRule struct {
+	calls uint64 // NOTE: first field to keep 64-bit alignment for atomic
+	InjectRule
+}
+
+func (r *injectRule) match(ctx context.Context) bool {
+	if len(r.Tenants) > 0 {
+		tenant, ok := injectTenant(ExtractContext(ctx)[InjectTenantField])
+		if !ok {
+			return false
+		}
+		found := false
+		for _, t := range r.Tenants {
+			if tenant == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.EveryN > 0 && atomic.AddUint64(&r.calls, 1)%uint64(r.EveryN) != 0 {
+		return false
+	}
+	return r.Probability == 0 || rand.Float64() < r.Probability
+}
+
+// injectTenant converts the tenant extracted from context to string, false if not found
+func injectTenant(v any) (string, bool) {
+	switch t := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return t, true
+	case fmt.Stringer:
+		return t.String(), true
+	default:
+		return fmt.Sprint(t), true
+	}
+}
+
+var (
+	injectRules = make(map[string][]*injectRule)
+	injectLock  sync.RWMutex
+)
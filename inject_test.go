@@ -0,0 +1,127 @@
+//go:build errors_inject
+// +build errors_inject
+
+package errors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInject(t *testing.T) {
+	defer errors.RemoveInjectRules("")
+	assert.Truef(t, errors.InjectEnabled, "inject enabled")
+	assert.Nilf(t, errors.Inject("orders.db.save"), "no rule")
+
+	err := errors.AddInjectRule(errors.InjectRule{
+		Point:      "orders.db.save",
+		RetryAfter: time.Second,
+		Options:    []errors.Option{errors.MessageOption("chaos")},
+	})
+	assert.Nilf(t, err, "add rule")
+	err = errors.Inject("orders.db.save")
+	assert.Truef(t, errors.IsCauseOrLatestMetaError(err, errors.Unavailable), "default to unavailable")
+	assert.Equalf(t, time.Second, errors.RetryAfterAttr.Get(err), "retry after")
+	assert.Equalf(t, "orders.db.save", errors.InjectPointAttr.Get(err), "inject point")
+	assert.Equalf(t, "chaos", errors.MessageAttr.Get(err), "message")
+	assert.Equalf(t, "fault injected at orders.db.save", errors.Cause(err).Error(), "cause")
+	assert.Nilf(t, errors.Inject("orders.db.load"), "other point")
+
+	errors.RemoveInjectRules("orders.db.save")
+	assert.Nilf(t, errors.Inject("orders.db.save"), "rule removed")
+
+	assert.Truef(t, errors.Is(errors.AddInjectRule(errors.InjectRule{}), errors.InvalidArgument), "rule without point")
+	assert.Truef(t, errors.Is(errors.AddInjectRule(errors.InjectRule{Point: "x", Probability: 2}), errors.InvalidArgument), "probability out of range")
+}
+
+func TestInjectEveryN(t *testing.T) {
+	defer errors.RemoveInjectRules("")
+	errors.AddInjectRule(errors.InjectRule{Point: "p", Error: errors.Aborted, EveryN: 3})
+	errors.AddInjectRule(errors.InjectRule{Point: "p", Error: errors.Internal, EveryN: 2})
+	var got []string
+	for i := 0; i < 6; i++ {
+		if err := errors.Inject("p"); err != nil {
+			got = append(got, errors.GetCode(err))
+		} else {
+			got = append(got, "")
+		}
+	}
+	// NOTE: the second rule is only evaluated when the first one not matched
+	assert.Equalf(t, []string{"", "internal(13)", "aborted(10)", "", "internal(13)", "aborted(10)"}, got, "every n")
+}
+
+func TestInjectProbability(t *testing.T) {
+	defer errors.RemoveInjectRules("")
+	errors.AddInjectRule(errors.InjectRule{Point: "p", Probability: 0.5})
+	count := 0
+	for i := 0; i < 1000; i++ {
+		if errors.Inject("p") != nil {
+			count++
+		}
+	}
+	assert.InDeltaf(t, 500, count, 150, "probability")
+}
+
+func TestInjectTenant(t *testing.T) {
+	defer errors.RemoveInjectRules("")
+	errors.RegisterContextValue(errors.InjectTenantField, ctxKey("tenant"))
+	defer errors.UnregisterContextExtractor(errors.InjectTenantField)
+	errors.AddInjectRule(errors.InjectRule{Point: "p", Tenants: []string{"t1"}})
+
+	assert.NotNilf(t, errors.InjectCtx(context.WithValue(context.Background(), ctxKey("tenant"), "t1"), "p"), "tenant matched")
+	assert.Nilf(t, errors.InjectCtx(context.WithValue(context.Background(), ctxKey("tenant"), "t2"), "p"), "tenant not matched")
+	assert.Nilf(t, errors.InjectCtx(context.Background(), "p"), "no tenant")
+	assert.Nilf(t, errors.Inject("p"), "no context")
+
+	errors.RegisterContextValue(errors.InjectTenantField, ctxKey("tenant_id"))
+	assert.NotNilf(t, errors.InjectCtx(context.WithValue(context.Background(), ctxKey("tenant_id"), tenantID("t1")), "p"), "stringer tenant")
+	assert.Nilf(t, errors.InjectCtx(context.WithValue(context.Background(), ctxKey("tenant_id"), tenantID("t2")), "p"), "stringer tenant")
+}
+
+type tenantID string
+
+func (t tenantID) String() string { return string(t) }
+
+func TestInjectHandler(t *testing.T) {
+	defer errors.RemoveInjectRules("")
+	h := errors.InjectHandler()
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+
+	id := errors.MetaAttr.Get(errors.ResourceExhausted).ID()
+	w := do(http.MethodPost, "/", `{"point":"p","error":"`+id+`","retry_after":"2s","every_n":1,"tenants":["t1"]}`)
+	assert.Equalf(t, http.StatusOK, w.Code, "add rule")
+	assert.JSONEqf(t, `[{"point":"p","error":"`+id+`","retry_after":"2s","every_n":1,"tenants":["t1"]}]`, w.Body.String(), "rules added")
+	rules := errors.InjectRules()
+	if assert.Lenf(t, rules, 1, "rules") {
+		assert.Equalf(t, errors.ResourceExhausted, rules[0].Error, "rule error")
+		assert.Equalf(t, 2*time.Second, rules[0].RetryAfter, "rule retry after")
+	}
+
+	w = do(http.MethodPost, "/", `{"point":"p","error":"not exists"}`)
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "unknown error")
+	w = do(http.MethodPost, "/", `{"point":"p","retry_after":"x"}`)
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "invalid retry after")
+	w = do(http.MethodPatch, "/", "")
+	assert.Equalf(t, http.StatusMethodNotAllowed, w.Code, "method not allowed")
+	assert.Equalf(t, "GET, POST, PUT, DELETE", w.Header().Get("Allow"), "allowed methods")
+	assert.Equalf(t, "invalid_argument(3)", errors.HTTPBody(errors.MethodNotAllowed)["meta.code"], "body")
+
+	do(http.MethodPost, "/", `{"point":"q"}`)
+	w = do(http.MethodGet, "/", "")
+	assert.JSONEqf(t, `[{"point":"p","error":"`+id+`","retry_after":"2s","every_n":1,"tenants":["t1"]},{"point":"q"}]`, w.Body.String(), "list rules")
+	w = do(http.MethodDelete, "/?point=p", "")
+	assert.JSONEqf(t, `[{"point":"q"}]`, w.Body.String(), "rules of point deleted")
+	w = do(http.MethodDelete, "/", "")
+	assert.JSONEqf(t, `[]`, w.Body.String(), "all rules deleted")
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Import preserves the structure of err created by other error libraries(e.g. github.com/cockroachdb/errors,
+// github.com/hashicorp/go-multierror, go.uber.org/multierr), the foreign error is kept as the cause, and the following
+// duck-typed interfaces found by unwrapping it are exposed as attrs under the attrs already attached on err:
+//
+//     StackTrace() pkgerrors.StackTrace // stack of pkg/errors & cockroachdb/errors => `StackAttr`
+//     ErrorHint() string                // hint of cockroachdb/errors => `MessageAttr`
+//     ErrorDetail() string              // detail of cockroachdb/errors => `MessageAttr`
+//     WrappedErrors() []error           // children of hashicorp/go-multierror => `ErrorAttr`
+//     Errors() []error                  // children of go.uber.org/multierr => `ErrorAttr`
+//     Unwrap() []error                  // children of std `errors.Join` & cockroachdb/errors => `ErrorAttr`
+//
+// children are imported recursively, err is returned as is if nothing found or it has been imported already(anywhere in
+// the chain), adapters created with `WithImport` import errors on `Adapt`
+//
+// Usage:
+//
+//     err := errors.Import(crdberrors.WithHint(crdberrors.New("conn refused"), "check the dsn"))
+//     errors.MessageAttr.Get(err) // check the dsn
+//     errors.StackAttr.Get(err)   // stack of crdberrors.New
+func Import(err error) error {
+	var top []*valueError // NOTE: nodes attached on the foreign error, including invisible ones(e.g. marker of `FromContext`)
+	base := err
+	for {
+		ve, ok := base.(*valueError)
+		if !ok {
+			break
+		}
+		top = append(top, ve)
+		base = ve.error
+	}
+	var imported *importedError
+	if base == nil || base == empty || stderrors.As(err, &imported) {
+		return err
+	}
+	layers := foreignLayers(base)
+	if len(layers) == 0 {
+		return err
+	}
+	var result error = &importedError{base}
+	for i := len(layers) - 1; i >= 0; i-- {
+		result = &valueError{result, layers[i].Key, layers[i].Value}
+	}
+	for i := len(top) - 1; i >= 0; i-- {
+		result = &valueError{result, top[i].key, top[i].val}
+	}
+	return result
+}
+
+// foreignLayers returns the attrs found by unwrapping err, from the outermost to the innermost
+func foreignLayers(err error) []Node {
+	var layers []Node
+	for err != nil {
+		if _, ok := err.(*fundamental); !ok { // NOTE: stack of fundamental is exposed by `StackTrace` of the chain
+			if st, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok {
+				if trace := st.StackTrace(); len(trace) > 0 {
+					s := make(stack, len(trace))
+					for i, f := range trace {
+						s[i] = uintptr(f)
+					}
+					layers = append(layers, Node{Key: StackAttr.Key(), Value: &s})
+				}
+			}
+		}
+		if h, ok := err.(interface{ ErrorHint() string }); ok && h.ErrorHint() != "" {
+			layers = append(layers, Node{Key: MessageAttr.Key(), Value: h.ErrorHint()})
+		}
+		if d, ok := err.(interface{ ErrorDetail() string }); ok && d.ErrorDetail() != "" {
+			layers = append(layers, Node{Key: MessageAttr.Key(), Value: d.ErrorDetail()})
+		}
+		var children []error
+		switch e := err.(type) {
+		case interface{ WrappedErrors() []error }:
+			children = e.WrappedErrors()
+		case interface{ Errors() []error }:
+			children = e.Errors()
+		case interface{ Unwrap() []error }:
+			children = e.Unwrap()
+		}
+		if children != nil {
+			for i := len(children) - 1; i >= 0; i-- { // NOTE: the first child is the oldest
+				if children[i] != nil {
+					layers = append(layers, Node{Key: ErrorAttr.Key(), Value: Import(children[i])})
+				}
+			}
+			break
+		}
+		switch e := err.(type) {
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			err = nil
+		}
+	}
+	return layers
+}
+
+// importedError marks the foreign error which has been imported, it's transparent to `Cause`, `errors.Is` and
+// `errors.As`, and formatted as the text of the foreign error since its stacks are imported as attrs
+type importedError struct {
+	error
+}
+
+func (e *importedError) Cause() error  { return e.error }
+func (e *importedError) Unwrap() error { return e.error }
+
+func (e *importedError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.error)
+}
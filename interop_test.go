@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// withHint & withDetail imitate github.com/cockroachdb/errors/hintdetail
+type withHint struct {
+	cause error
+	hint  string
+}
+
+func (w *withHint) Error() string     { return w.cause.Error() }
+func (w *withHint) Cause() error      { return w.cause }
+func (w *withHint) Unwrap() error     { return w.cause }
+func (w *withHint) ErrorHint() string { return w.hint }
+
+type withDetail struct {
+	cause  error
+	detail string
+}
+
+func (w *withDetail) Error() string       { return w.cause.Error() }
+func (w *withDetail) Unwrap() error       { return w.cause }
+func (w *withDetail) ErrorDetail() string { return w.detail }
+
+// multiError imitate github.com/hashicorp/go-multierror
+type multiError struct {
+	errs []error
+}
+
+func (m *multiError) Error() string          { return fmt.Sprintf("%d errors occurred", len(m.errs)) }
+func (m *multiError) WrappedErrors() []error { return m.errs }
+func (m *multiError) Unwrap() error          { return m.errs[0] }
+func (m *multiError) Errors() []error        { panic("WrappedErrors is preferred") }
+
+// joinError imitate std errors.Join
+type joinError struct {
+	errs []error
+}
+
+func (j *joinError) Error() string   { return "joined" }
+func (j *joinError) Unwrap() []error { return j.errs }
+
+func TestImport(t *testing.T) {
+	foreign := &withHint{&withDetail{pkgerrors.WithStack(io.EOF), "dsn=localhost"}, "check the dsn"}
+	err := errors.Import(foreign)
+	assert.Equalf(t, []string{"dsn=localhost", "check the dsn"}, errors.MessageAttr.GetAll(err), "oldest first")
+	assert.Equalf(t, "check the dsn", errors.MessageAttr.Get(err), "latest message")
+	assert.NotNilf(t, errors.StackAttr.Get(err), "stack imported")
+	assert.Regexpf(t, `TestImport\n\t.+/interop_test.go:\d+`, fmt.Sprintf("%+v", errors.StackAttr.Get(err)), "stack of pkg/errors")
+	assert.Equalf(t, io.EOF, errors.Cause(err), "foreign cause")
+	assert.Truef(t, errors.Is(err, io.EOF), "transparent to Is")
+	var hint *withHint
+	assert.Truef(t, errors.As(err, &hint), "transparent to As")
+	assert.Equalf(t, foreign, hint, "foreign error is kept")
+	assert.Equalf(t, err, errors.Import(err), "imported already")
+	assert.Equalf(t, io.EOF, errors.Import(io.EOF), "nothing found")
+	assert.Nilf(t, errors.Import(nil), "nil")
+	newErr := errors.New("ours")
+	assert.Equalf(t, newErr, errors.Import(newErr), "stack of New is not imported")
+
+	err = errors.Import(errors.WithError(errors.WithMessage(foreign, "ours"), errors.NotFound))
+	assert.Equalf(t, []string{"dsn=localhost", "check the dsn", "ours"}, errors.MessageAttr.GetAll(err), "imported attrs are older")
+	assert.Equalf(t, 404, errors.StatusAttr.Get(err), "status of attached meta")
+	assert.Equalf(t, errors.NotFound, errors.GetLatestMetaError(err), "attached meta is the latest")
+	assert.Equalf(t, err, errors.Import(err), "imported already")
+}
+
+func TestImportMultiErrors(t *testing.T) {
+	child := &withHint{io.ErrUnexpectedEOF, "retry later"}
+	err := errors.Import(&multiError{errs: []error{io.EOF, child}})
+	errs := errors.GetAllErrors(err)
+	assert.Lenf(t, errs, 3, "multi error and its children")
+	assert.Equalf(t, io.EOF, errs[1], "first child is the oldest")
+	assert.Equalf(t, "retry later", errors.MessageAttr.Get(errs[2]), "children imported recursively")
+	assert.Equalf(t, []string{"retry later"}, errors.MessageAttr.GetAll(err), "nested lookup")
+	assert.Equalf(t, "2 errors occurred", errors.Cause(err).Error(), "cause is the multi error")
+
+	err = errors.Import(&joinError{errs: []error{io.EOF, nil, io.ErrUnexpectedEOF}})
+	assert.Equalf(t, []error{io.EOF, io.ErrUnexpectedEOF}, errors.ErrorAttr.GetAll(err), "nil children skipped")
+}
+
+func TestAdaptImport(t *testing.T) {
+	adapter := errors.NewDefaultAdapter(errors.WithImport())
+	err := adapter.Adapt(&withHint{io.EOF, "check the dsn"}, errors.Unavailable)
+	assert.Equalf(t, "check the dsn", errors.MessageAttr.Get(err), "imported")
+	assert.Equalf(t, errors.Unavailable, errors.GetLatestMetaError(err), "adapted")
+	assert.Equalf(t, 503, errors.StatusAttr.Get(err), "adapted")
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	again := adapter.Adapt(wrapped, errors.Unavailable)
+	assert.Equalf(t, 1, strings.Count(again.Error(), "check the dsn"), "imported already behind a foreign wrapper: %s", again)
+	assert.Truef(t, strings.HasPrefix(again.Error(), "wrapped: EOF:msg={check the dsn}:"), "text of the wrapper kept: %s", again)
+}
+
+func TestAdaptNotImportByDefault(t *testing.T) {
+	err := errors.Adapt(pkgerrors.Wrap(io.EOF, "read header"), errors.NotFound)
+	assert.Regexpf(t, `^read header: EOF:error=\{meta=\{source=errors;code=not_found\(5\)\}:status=\{404\}\}:caller=\{errors_test\.TestAdaptNotImportByDefault:\d+\}$`, err.Error(), "output unchanged")
+	assert.Nilf(t, errors.StackAttr.Get(err), "stack not imported")
+	assert.Emptyf(t, errors.MessageAttr.GetAll(errors.Adapt(&withHint{io.EOF, "check the dsn"}, errors.Unavailable)), "hint not imported")
+}
+
+func TestImportFormat(t *testing.T) {
+	err := errors.With(errors.Import(pkgerrors.Wrap(io.EOF, "read header")), errors.FormatModeAttr.Option(errors.PkgErrors))
+	assert.Equalf(t, "read header: EOF", err.Error(), "text of the foreign error")
+	assert.Equalf(t, 1, strings.Count(fmt.Sprintf("%+v", err), "TestImportFormat"), "imported stack printed once: %+v", err)
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	stderrors "errors"
+	"reflect"
+)
+
+// Matcher reports whether err matches, used in tests, retry policies and error routing
+//
+// Usage:
+//
+//     if errors.Match(err, errors.HasMeta(errors.NotFound), errors.HasAttr(errors.StatusAttr, 404)) {
+//         ...
+//     }
+//
+//     switch errors.Classify(err,
+//         errors.When("retry", errors.Latest(errors.Any(errors.HasMeta(errors.Unavailable), errors.HasMeta(errors.Aborted)))),
+//         errors.When("eof", errors.CauseIs(io.EOF)),
+//     ) {
+//     case "retry":
+//         ...
+//     case "eof":
+//         ...
+//     }
+type Matcher func(error) bool
+
+// Match reports whether err matches all matchers, nil err never matches
+func Match(err error, matchers ...Matcher) bool {
+	if err == nil {
+		return false
+	}
+	return All(matchers...)(err)
+}
+
+// HasMeta matches if any meta attached in err(including nested errors) is the meta of me
+func HasMeta(me MetaError) Matcher {
+	target := MetaAttr.Get(me)
+	return func(err error) bool {
+		if target == nil {
+			return false
+		}
+		for _, m := range MetaAttr.GetAll(err) {
+			if m == target || (m != nil && m.ID() == target.ID()) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasAttr matches if the value of attr in err deeply equals to want
+func HasAttr[T any](attr *Attr[T], want T) Matcher {
+	return func(err error) bool {
+		return reflect.DeepEqual(attr.Get(err), want)
+	}
+}
+
+// AttrMatches matches if the value of attr in err satisfies fn
+func AttrMatches[T any](attr *Attr[T], fn func(T) bool) Matcher {
+	return func(err error) bool {
+		return fn(attr.Get(err))
+	}
+}
+
+// CauseIs matches if the cause of err is target, std `errors.Is` semantic is used on the cause
+func CauseIs(target error) Matcher {
+	return func(err error) bool {
+		return stderrors.Is(Cause(err), target)
+	}
+}
+
+// Latest matches if the latest error attached by `WithError`(or the cause if none) matches all matchers
+func Latest(matchers ...Matcher) Matcher {
+	m := All(matchers...)
+	return func(err error) bool {
+		errs := GetAllErrors(err)
+		return m(errs[len(errs)-1])
+	}
+}
+
+// Any matches if any of matchers matches
+func Any(matchers ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range matchers {
+			if m(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All matches if all matchers match, empty matchers always match
+func All(matchers ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range matchers {
+			if !m(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not matches if matcher does not match
+func Not(matcher Matcher) Matcher {
+	return func(err error) bool {
+		return !matcher(err)
+	}
+}
+
+// Case is a labeled matcher used by `Classify`
+type Case struct {
+	Label   string
+	Matcher Matcher
+}
+
+// When creates a `Case` which matches if all matchers match
+func When(label string, matchers ...Matcher) Case {
+	return Case{
+		Label:   label,
+		Matcher: All(matchers...),
+	}
+}
+
+// Classify returns the label of the first case which err matches, returns empty string if err is nil or no case matched
+func Classify(err error, cases ...Case) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range cases {
+		if c.Matcher(err) {
+			return c.Label
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	err := errors.WithError(fmt.Errorf("read: %w", io.EOF), errors.NotFound)
+	err = errors.WithError(err, errors.Unavailable)
+
+	assert.Truef(t, errors.Match(err, errors.HasMeta(errors.NotFound), errors.HasMeta(errors.Unavailable)), "all matchers match")
+	assert.Falsef(t, errors.Match(err, errors.HasMeta(errors.Aborted)), "meta not attached")
+	assert.Truef(t, errors.Match(err, errors.HasAttr(errors.StatusAttr, 503)), "status of latest meta")
+	assert.Falsef(t, errors.Match(err, errors.HasAttr(errors.StatusAttr, 404)), "status of former meta overridden")
+	assert.Truef(t, errors.Match(err, errors.AttrMatches(errors.StatusAttr, func(status int) bool { return status >= 500 })), "attr matches")
+	assert.Truef(t, errors.Match(err, errors.CauseIs(io.EOF)), "std errors.Is on cause")
+	assert.Falsef(t, errors.Match(err, errors.CauseIs(io.ErrUnexpectedEOF)), "std errors.Is on cause")
+	assert.Truef(t, errors.Match(err, errors.Latest(errors.HasMeta(errors.Unavailable))), "latest meta")
+	assert.Falsef(t, errors.Match(err, errors.Latest(errors.HasMeta(errors.NotFound))), "former meta is not latest")
+	assert.Truef(t, errors.Match(io.EOF, errors.Latest(errors.CauseIs(io.EOF))), "latest is cause if no error attached")
+	assert.Truef(t, errors.Match(err, errors.Any(errors.HasMeta(errors.Aborted), errors.HasMeta(errors.NotFound))), "any of matchers")
+	assert.Falsef(t, errors.Match(err, errors.Any()), "empty any never matches")
+	assert.Truef(t, errors.Match(err, errors.All()), "empty all always matches")
+	assert.Truef(t, errors.Match(err, errors.Not(errors.HasMeta(errors.Aborted))), "not")
+	assert.Truef(t, errors.Match(err), "no matchers")
+	assert.Falsef(t, errors.Match(nil, errors.Not(errors.HasMeta(errors.Aborted))), "nil never matches")
+	assert.Falsef(t, errors.Match(err, errors.HasMeta(nil)), "nil meta never matches")
+}
+
+func TestClassify(t *testing.T) {
+	cases := []errors.Case{
+		errors.When("retry", errors.Latest(errors.Any(errors.HasMeta(errors.Unavailable), errors.HasMeta(errors.Aborted)))),
+		errors.When("eof", errors.CauseIs(io.EOF)),
+		errors.When("client", errors.AttrMatches(errors.StatusAttr, func(status int) bool { return status/100 == 4 }), errors.Not(errors.HasMeta(errors.NotFound))),
+	}
+	for _, c := range []struct {
+		err  error
+		want string
+	}{
+		{errors.WithError(io.EOF, errors.Aborted), "retry"},
+		{errors.WithError(errors.WithError(io.EOF, errors.Aborted), errors.NotFound), "eof"},
+		{errors.WithError(errors.New("xxx"), errors.InvalidArgument), "client"},
+		{errors.WithError(errors.New("xxx"), errors.NotFound), ""},
+		{nil, ""},
+	} {
+		assert.Equalf(t, c.want, errors.Classify(c.err, cases...), "%v", c.err)
+	}
+}
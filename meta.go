@@ -106,19 +106,19 @@ func pkgName(source string) string {
 // RegisterMetaError register MetaError into metaErrors registry, return error is exists
 func RegisterMetaError(me MetaError) error {
 	if me == nil {
-		return New("register nil meta error, just ignore")
+		return fmt.Errorf("register nil meta error, just ignore")
 	}
 	if me.App() != AppName() {
-		return Errorf("meta error app(%s) != current app name %s", me.App(), AppName())
+		return fmt.Errorf("meta error app(%s) != current app name %s", me.App(), AppName())
 	}
 	id := MetaID(me.App(), me.Source(), me.Code())
 	if me.Source() == "" || me.Code() == "" || me.Message() == "" {
-		return Errorf("meta error(%s): source, code and msg can not be empty", id)
+		return fmt.Errorf("meta error(%s): source, code and msg can not be empty", id)
 	}
 	metaErrorsLock.Lock()
 	defer metaErrorsLock.Unlock()
 	if _, ok := metaErrors[id]; ok {
-		return Errorf("meta error %s already exists; use with_xxx to rebind", id)
+		return fmt.Errorf("meta error %s already exists; use with_xxx to rebind", id)
 	}
 	metaErrors[id] = me
 	return nil
@@ -161,7 +161,7 @@ func SetAppName(appNew string) error {
 		for _, me := range metaErrors {
 			idNew := MetaID(me.App(), me.Source(), me.Code())
 			if _, ok := metaErrors[idNew]; ok {
-				return Errorf("meta error(%s) already exists", idNew)
+				return fmt.Errorf("meta error(%s) already exists", idNew)
 			}
 			newMetaErrors[idNew] = me
 		}
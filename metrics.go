@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NewMetricsObserver creates an in-memory `Observer` which counts errors by app, source, code and status,
+// the counters can be exposed in Prometheus text exposition format by `ServeHTTP`
+//
+// Usage:
+//
+//     mo := errors.NewMetricsObserver(errors.ObserveAdapt)
+//     errors.AddObserver(mo)
+//     http.Handle("/metrics/errors", mo)
+func NewMetricsObserver(points ...ObservePoint) *MetricsObserver {
+	mo := MetricsObserver{
+		Name:     "errors_total",
+		counters: make(map[MetricsKey]uint64),
+	}
+	if len(points) > 0 {
+		mo.points = make(map[ObservePoint]bool, len(points))
+		for _, p := range points {
+			mo.points[p] = true
+		}
+	}
+	return &mo
+}
+
+// MetricsObserver counts errors by app, source, code and status
+type MetricsObserver struct {
+	// Name is the metric name, default to `errors_total`
+	Name string
+
+	points   map[ObservePoint]bool // NOTE: nil means all points
+	counters map[MetricsKey]uint64
+	lock     sync.RWMutex
+}
+
+// MetricsKey is the label set of error counter
+type MetricsKey struct {
+	App    string
+	Source string
+	Code   string
+	Status int
+}
+
+// Observe implement `Observer`
+func (mo *MetricsObserver) Observe(point ObservePoint, err error) {
+	if err == nil {
+		return
+	}
+	if mo.points != nil && !mo.points[point] {
+		return
+	}
+	key := MetricsKey{Status: StatusAttr.Get(err)}
+	if m := MetaAttr.Get(err); m != nil {
+		key.App, key.Source, key.Code = m.app(), m.source, m.code
+	}
+	mo.lock.Lock()
+	mo.counters[key]++
+	mo.lock.Unlock()
+}
+
+// Counters returns a copy of all counters
+func (mo *MetricsObserver) Counters() map[MetricsKey]uint64 {
+	mo.lock.RLock()
+	defer mo.lock.RUnlock()
+	counters := make(map[MetricsKey]uint64, len(mo.counters))
+	for k, v := range mo.counters {
+		counters[k] = v
+	}
+	return counters
+}
+
+// Reset clear all counters
+func (mo *MetricsObserver) Reset() {
+	mo.lock.Lock()
+	defer mo.lock.Unlock()
+	mo.counters = make(map[MetricsKey]uint64)
+}
+
+// WriteTo write counters in Prometheus text exposition format
+func (mo *MetricsObserver) WriteTo(w io.Writer) (int64, error) {
+	counters := mo.Counters()
+	keys := make([]MetricsKey, 0, len(counters))
+	for k := range counters {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Status < b.Status
+	})
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# HELP %s Total number of errors by app, source, code and status.\n", mo.Name)
+	fmt.Fprintf(&sb, "# TYPE %s counter\n", mo.Name)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s{app=\"%s\",source=\"%s\",code=\"%s\",status=\"%d\"} %s\n",
+			mo.Name, escapeLabelValue(k.App), escapeLabelValue(k.Source), escapeLabelValue(k.Code), k.Status,
+			strconv.FormatUint(counters[k], 10))
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// ServeHTTP implement `http.Handler` to expose counters in Prometheus text exposition format
+func (mo *MetricsObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	mo.WriteTo(w)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+var (
+	_ Observer     = (*MetricsObserver)(nil)
+	_ http.Handler = (*MetricsObserver)(nil)
+)
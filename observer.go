@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ObservePoint is where the error is observed
+type ObservePoint string
+
+const (
+	// ObserveAdapt error observed when `Adapt`
+	ObserveAdapt ObservePoint = "adapt"
+
+	// ObserveHTTP error observed when `WriteHTTP`
+	ObserveHTTP ObservePoint = "http"
+
+	// ObserveWithError error observed when `WithError`, only if `SetObserveWithError(true)`
+	ObserveWithError ObservePoint = "with_error"
+)
+
+// Observer observes errors when `Adapt`, `WriteHTTP` and optionally `WithError`, usually used to collect metrics
+type Observer interface {
+	Observe(point ObservePoint, err error)
+}
+
+// ObserverFunc is a function which implements `Observer`
+type ObserverFunc func(point ObservePoint, err error)
+
+// Observe implement `Observer`
+func (fn ObserverFunc) Observe(point ObservePoint, err error) {
+	fn(point, err)
+}
+
+// SetObserver install the observer and remove all observers installed before, nil means no observer
+func SetObserver(o Observer) {
+	observerLock.Lock()
+	defer observerLock.Unlock()
+	s := *loadObserverState()
+	s.observers = nil
+	if o != nil {
+		s.observers = []*observerEntry{{o}}
+	}
+	observerState.Store(&s)
+}
+
+// AddObserver install the observer besides the installed ones(e.g. `MetricsObserver`, `RecentErrors` and the observer
+// of `Sampler` together), observers are called in the order they are added, returns a function to remove it
+//
+// Usage:
+//
+//     remove := errors.AddObserver(errors.NewMetricsObserver(errors.ObserveAdapt))
+//     defer remove()
+func AddObserver(o Observer) (remove func()) {
+	if o == nil {
+		return func() {}
+	}
+	entry := &observerEntry{o}
+	observerLock.Lock()
+	defer observerLock.Unlock()
+	s := *loadObserverState()
+	s.observers = append(s.observers[:len(s.observers):len(s.observers)], entry) // NOTE: copy on write, see `observe`
+	observerState.Store(&s)
+	return func() {
+		observerLock.Lock()
+		defer observerLock.Unlock()
+		s := *loadObserverState()
+		for i, e := range s.observers {
+			if e == entry {
+				rest := make([]*observerEntry, 0, len(s.observers)-1)
+				s.observers = append(append(rest, s.observers[:i]...), s.observers[i+1:]...)
+				observerState.Store(&s)
+				return
+			}
+		}
+	}
+}
+
+// MultiObserver returns an `Observer` which calls observers in order, nil observers are ignored
+func MultiObserver(observers ...Observer) Observer {
+	return ObserverFunc(func(point ObservePoint, err error) {
+		for _, o := range observers {
+			if o != nil {
+				o.Observe(point, err)
+			}
+		}
+	})
+}
+
+// SetObserveWithError specify whether to observe errors when `WithError`, default to false
+func SetObserveWithError(enable bool) {
+	observerLock.Lock()
+	defer observerLock.Unlock()
+	s := *loadObserverState()
+	s.withError = enable
+	observerState.Store(&s)
+}
+
+func observe(point ObservePoint, err error) error {
+	s := loadObserverState()
+	if len(s.observers) == 0 {
+		return err
+	}
+	for _, e := range s.observers { // NOTE: called on the snapshot, so observers can install observers
+		e.Observe(point, err)
+	}
+	return err
+}
+
+func observeWithError() bool {
+	s := loadObserverState()
+	return s.withError && len(s.observers) > 0
+}
+
+// observerEntry wraps an observer so that it can be removed by identity(e.g. `ObserverFunc` is not comparable)
+type observerEntry struct {
+	Observer
+}
+
+type observerStateT struct {
+	observers []*observerEntry // NOTE: never modified in place, replaced on write
+	withError bool
+}
+
+func loadObserverState() *observerStateT {
+	if s, ok := observerState.Load().(*observerStateT); ok {
+		return s
+	}
+	return &observerStateT{}
+}
+
+var (
+	// NOTE: atomic.Value used to keep zero overhead(no lock) when observing, writers are serialized by observerLock
+	observerState atomic.Value // *observerStateT
+	observerLock  sync.Mutex
+)
@@ -0,0 +1,116 @@
+package errors_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	var points []errors.ObservePoint
+	errors.SetObserver(errors.ObserverFunc(func(point errors.ObservePoint, err error) {
+		points = append(points, point)
+	}))
+	defer errors.SetObserver(nil)
+
+	err := errors.WithError(errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, 0, len(points), "with error not observed by default")
+	err = errors.Adapt(err, errors.Unknown)
+	errors.WriteHTTP(httptest.NewRecorder(), err)
+	errors.WriteHTTP(httptest.NewRecorder(), nil)
+	assert.Equalf(t, []errors.ObservePoint{errors.ObserveAdapt, errors.ObserveHTTP}, points, "adapt & http")
+
+	errors.SetObserveWithError(true)
+	defer errors.SetObserveWithError(false)
+	points = nil
+	errors.Adapt(errors.WithError(errors.New("xxx"), errors.NotFound), errors.Unknown)
+	errors.Adapt(errors.New("xxx"), errors.Unknown)
+	assert.Equalf(t, []errors.ObservePoint{errors.ObserveWithError, errors.ObserveAdapt, errors.ObserveAdapt}, points, "with error")
+}
+
+func TestAddObserver(t *testing.T) {
+	var calls []string
+	record := func(name string) errors.Observer {
+		return errors.ObserverFunc(func(point errors.ObservePoint, err error) {
+			calls = append(calls, name)
+		})
+	}
+	errors.SetObserver(record("set"))
+	defer errors.SetObserver(nil)
+	removeA := errors.AddObserver(record("a"))
+	removeB := errors.AddObserver(errors.MultiObserver(record("b1"), nil, record("b2")))
+	errors.Adapt(errors.New("xxx"), errors.Unknown)
+	assert.Equalf(t, []string{"set", "a", "b1", "b2"}, calls, "fan out in order")
+
+	calls = nil
+	removeA()
+	removeA()
+	errors.Adapt(errors.New("xxx"), errors.Unknown)
+	assert.Equalf(t, []string{"set", "b1", "b2"}, calls, "removed")
+
+	calls = nil
+	errors.SetObserver(nil)
+	removeB()
+	errors.Adapt(errors.New("xxx"), errors.Unknown)
+	assert.Emptyf(t, calls, "all removed by SetObserver")
+}
+
+func TestObserverConcurrent(t *testing.T) {
+	defer errors.SetObserver(nil)
+	defer errors.SetObserveWithError(false)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			remove := errors.AddObserver(errors.ObserverFunc(func(point errors.ObservePoint, err error) {}))
+			errors.SetObserveWithError(i%2 == 0)
+			errors.Adapt(errors.WithError(errors.New("xxx"), errors.NotFound), errors.Unknown)
+			remove()
+		}(i)
+	}
+	wg.Wait()
+	errors.SetObserveWithError(true)
+	var points []errors.ObservePoint
+	errors.SetObserver(errors.ObserverFunc(func(point errors.ObservePoint, err error) {
+		points = append(points, point)
+	}))
+	errors.WithError(errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, []errors.ObservePoint{errors.ObserveWithError}, points, "setters not lost")
+}
+
+func TestMetricsObserver(t *testing.T) {
+	mo := errors.NewMetricsObserver(errors.ObserveAdapt)
+	errors.SetObserver(mo)
+	defer errors.SetObserver(nil)
+
+	errors.Adapt(errors.New("xxx"), errors.NotFound)
+	errors.Adapt(errors.WithError(errors.New("xxx"), errors.NotFound), errors.Unknown)
+	errors.Adapt(errors.New("xxx"), errors.Unknown)
+	errors.WriteHTTP(httptest.NewRecorder(), errors.NotFound)
+	counters := mo.Counters()
+	assert.Equalf(t, uint64(2), counters[errors.MetricsKey{App: "myapp", Source: "github.com/ccmonky/errors", Code: "not_found(5)", Status: 404}], "not found")
+	assert.Equalf(t, uint64(1), counters[errors.MetricsKey{App: "myapp", Source: "github.com/ccmonky/errors", Code: "unknown(2)", Status: 500}], "unknown")
+
+	w := httptest.NewRecorder()
+	mo.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equalf(t, `# HELP errors_total Total number of errors by app, source, code and status.
+# TYPE errors_total counter
+errors_total{app="myapp",source="github.com/ccmonky/errors",code="not_found(5)",status="404"} 2
+errors_total{app="myapp",source="github.com/ccmonky/errors",code="unknown(2)",status="500"} 1
+`, w.Body.String(), "prometheus text")
+
+	mo.Reset()
+	assert.Equalf(t, 0, len(mo.Counters()), "reset")
+}
+
+func BenchmarkAdaptWithoutObserver(b *testing.B) {
+	err := errors.WithError(errors.New("xxx"), errors.NotFound)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		errors.Adapt(err, errors.Unknown)
+	}
+}
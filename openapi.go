@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OpenAPIErrorSchemaName is the name of error body schema in `components.schemas`
+var OpenAPIErrorSchemaName = "Error"
+
+// OpenAPIComponents returns OpenAPI 3.1 `components.responses` and `components.schemas` generated from all registered
+// MetaErrors, responses are grouped by `StatusAttr` and named as `Error{status}`, each response contains the example
+// bodies(the same as `HTTPBody`) of MetaErrors with that status, examples are named as `{source}.{code}` with invalid
+// chars replaced by `_`(e.g. `github.com_ccmonky_errors.not_found_5_`)
+//
+// Usage:
+//
+//     data, _ := json.Marshal(map[string]any{"openapi": "3.1.0", "components": errors.OpenAPIComponents()})
+func OpenAPIComponents() map[string]any {
+	groups := make(map[int][]MetaError)
+	for _, me := range AllMetaErrors() {
+		status := StatusAttr.Get(me)
+		groups[status] = append(groups[status], me)
+	}
+	responses := make(map[string]any, len(groups))
+	for status, mes := range groups {
+		responses[OpenAPIResponseName(status)] = openAPIResponse(status, mes)
+	}
+	return map[string]any{
+		"responses": responses,
+		"schemas": map[string]any{
+			OpenAPIErrorSchemaName: openAPIErrorSchema(),
+		},
+	}
+}
+
+// OpenAPIResponseName returns the name of response in `components.responses` for status
+func OpenAPIResponseName(status int) string {
+	return fmt.Sprintf("Error%d", status)
+}
+
+// RegisterOperationErrors annotate the MetaErrors which the operation(specified by OpenAPI operationId) may return,
+// multiple calls will be merged
+func RegisterOperationErrors(operationID string, mes ...MetaError) {
+	operationErrorsLock.Lock()
+	defer operationErrorsLock.Unlock()
+	operationErrors[operationID] = append(operationErrors[operationID], mes...)
+}
+
+// OpenAPIOperationResponses returns the OpenAPI `responses` of operation which keyed by status and only contains the
+// examples of MetaErrors registered by `RegisterOperationErrors`
+func OpenAPIOperationResponses(operationID string) map[string]any {
+	operationErrorsLock.RLock()
+	mes := operationErrors[operationID]
+	operationErrorsLock.RUnlock()
+	groups := make(map[int][]MetaError)
+	for _, me := range mes {
+		status := StatusAttr.Get(me)
+		groups[status] = append(groups[status], me)
+	}
+	responses := make(map[string]any, len(groups))
+	for status, mes := range groups {
+		responses[fmt.Sprint(status)] = openAPIResponse(status, mes)
+	}
+	return responses
+}
+
+func openAPIResponse(status int, mes []MetaError) map[string]any {
+	sort.Slice(mes, func(i, j int) bool {
+		return MetaID(mes[i].App(), mes[i].Source(), mes[i].Code()) < MetaID(mes[j].App(), mes[j].Source(), mes[j].Code())
+	})
+	examples := make(map[string]any, len(mes))
+	ids := make(map[string]string, len(mes)) // NOTE: example name => meta id
+	for _, me := range mes {
+		id := MetaID(me.App(), me.Source(), me.Code())
+		name := openAPIExampleName(me.Source(), me.Code())
+		if prev, ok := ids[name]; ok {
+			if prev == id { // NOTE: the same meta registered before app name changed
+				continue
+			}
+			name = openAPIExampleName(me.App(), me.Source(), me.Code()) // NOTE: the same source and code of different apps
+		}
+		ids[name] = id
+		examples[name] = map[string]any{
+			"summary": me.Message(),
+			"value":   HTTPBody(me),
+		}
+	}
+	description := http.StatusText(status)
+	if description == "" {
+		description = fmt.Sprintf("status %d", status)
+	}
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{
+					"$ref": "#/components/schemas/" + OpenAPIErrorSchemaName,
+				},
+				"examples": examples,
+			},
+		},
+	}
+}
+
+func openAPIErrorSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			MetaAttrAppFieldName:     map[string]any{"type": "string", "description": "app name"},
+			MetaAttrSourceFieldName:  map[string]any{"type": "string", "description": "source of the error code"},
+			MetaAttrCodeFieldName:    map[string]any{"type": "string", "description": "error code"},
+			MetaAttrMessageFieldName: map[string]any{"type": "string", "description": "error message"},
+		},
+		"required": []string{MetaAttrSourceFieldName, MetaAttrCodeFieldName, MetaAttrMessageFieldName},
+	}
+}
+
+// openAPIExampleName joins the full source(not the package name, since packages of different paths may have the same
+// name) and code as the example name, and replace invalid chars with `_`
+func openAPIExampleName(parts ...string) string {
+	return openAPIInvalidNameChars.ReplaceAllString(strings.Join(parts, "."), "_")
+}
+
+var (
+	openAPIInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+	operationErrors     = make(map[string][]MetaError)
+	operationErrorsLock sync.RWMutex
+)
@@ -0,0 +1,66 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIComponents(t *testing.T) {
+	components := errors.OpenAPIComponents()
+	data, err := json.Marshal(components["responses"].(map[string]any)["Error404"])
+	assert.Nilf(t, err, "marshal Error404")
+	assert.JSONEq(t, `{
+		"description": "Not Found",
+		"content": {
+			"application/json": {
+				"schema": {"$ref": "#/components/schemas/Error"},
+				"examples": {
+					"github.com_ccmonky_errors.not_found_5_": {
+						"summary": "not found",
+						"value": {
+							"meta.app": "myapp",
+							"meta.code": "not_found(5)",
+							"meta.message": "not found",
+							"meta.source": "github.com/ccmonky/errors"
+						}
+					}
+				}
+			}
+		}
+	}`, string(data), "Error404")
+	examples := components["responses"].(map[string]any)["Error409"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["examples"].(map[string]any)
+	assert.Containsf(t, examples, "github.com_ccmonky_errors.already_exists_6_", "409 contains already exists")
+	assert.Containsf(t, examples, "github.com_ccmonky_errors.aborted_10_", "409 contains aborted")
+	assert.NotContainsf(t, examples, "errors.already_exists_6_", "full source used")
+	schema := components["schemas"].(map[string]any)["Error"].(map[string]any)
+	assert.Equalf(t, "object", schema["type"], "schema type")
+	assert.Containsf(t, schema["properties"], "meta.code", "schema code")
+}
+
+func TestOpenAPIOperationResponses(t *testing.T) {
+	errors.RegisterOperationErrors("getUser", errors.NotFound)
+	errors.RegisterOperationErrors("getUser", errors.InvalidArgument, errors.FailedPrecondition)
+	responses := errors.OpenAPIOperationResponses("getUser")
+	assert.Equalf(t, 2, len(responses), "responses count")
+	examples := responses["400"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["examples"].(map[string]any)
+	assert.Equalf(t, 2, len(examples), "400 examples only contains registered")
+	assert.Containsf(t, responses, "404", "404 response")
+	assert.Equalf(t, 0, len(errors.OpenAPIOperationResponses("unknown")), "unknown operation")
+}
+
+var (
+	testConflictA = errors.NewMetaError("example.com/a/errors", "conflict", "conflict of a", errors.StatusOption(409))
+	testConflictB = errors.NewMetaError("example.com/b/errors", "conflict", "conflict of b", errors.StatusOption(409))
+)
+
+func TestOpenAPIExampleNameCollision(t *testing.T) {
+	errors.RegisterOperationErrors("openAPIExampleNameCollision", testConflictA, testConflictB)
+	responses := errors.OpenAPIOperationResponses("openAPIExampleNameCollision")
+	examples := responses["409"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["examples"].(map[string]any)
+	assert.Equalf(t, 2, len(examples), "not collided")
+	assert.Equalf(t, "conflict of a", examples["example.com_a_errors.conflict"].(map[string]any)["summary"], "example of a")
+	assert.Equalf(t, "conflict of b", examples["example.com_b_errors.conflict"].(map[string]any)["summary"], "example of b")
+}
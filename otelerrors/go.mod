@@ -0,0 +1,21 @@
+module github.com/ccmonky/errors/otelerrors
+
+go 1.18
+
+require (
+	github.com/ccmonky/errors v0.0.0
+	github.com/ccmonky/inithook v0.0.0-20230109081757-739280f6d563
+	github.com/stretchr/testify v1.8.2
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+)
+
+require (
+	github.com/ccmonky/log v0.0.0-20230113103641-7a2de39dc264 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/ccmonky/errors => ../
@@ -0,0 +1,67 @@
+// Package otelerrors adapts OpenTelemetry spans to `errors.Span`, so that errors can be recorded on spans by
+// `errors.Recorder` without a hard dependency on OpenTelemetry in package errors.
+package otelerrors
+
+import (
+	"fmt"
+
+	"github.com/ccmonky/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span wraps OpenTelemetry span as `errors.Span`
+func Span(span trace.Span) errors.Span {
+	return &otelSpan{span: span}
+}
+
+// RecordError records err on OpenTelemetry span by `errors.RecordError`
+func RecordError(span trace.Span, err error) {
+	errors.RecordError(Span(span), err)
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetAttributes implement `errors.Span`
+func (s *otelSpan) SetAttributes(attrs map[string]any) {
+	s.span.SetAttributes(KeyValues(attrs)...)
+}
+
+// AddEvent implement `errors.Span`
+func (s *otelSpan) AddEvent(name string, attrs map[string]any) {
+	s.span.AddEvent(name, trace.WithAttributes(KeyValues(attrs)...))
+}
+
+// SetError implement `errors.Span`
+func (s *otelSpan) SetError(description string) {
+	s.span.SetStatus(codes.Error, description)
+}
+
+// KeyValues converts attrs into OpenTelemetry attributes
+func KeyValues(attrs map[string]any) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		switch tv := v.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(k, tv))
+		case bool:
+			kvs = append(kvs, attribute.Bool(k, tv))
+		case int:
+			kvs = append(kvs, attribute.Int(k, tv))
+		case int64:
+			kvs = append(kvs, attribute.Int64(k, tv))
+		case float64:
+			kvs = append(kvs, attribute.Float64(k, tv))
+		default:
+			kvs = append(kvs, attribute.String(k, fmt.Sprint(tv)))
+		}
+	}
+	return kvs
+}
+
+var (
+	_ errors.Span = (*otelSpan)(nil)
+)
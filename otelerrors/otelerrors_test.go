@@ -0,0 +1,85 @@
+package otelerrors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/ccmonky/errors/otelerrors"
+	"github.com/ccmonky/inithook"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	err := inithook.ExecuteAttrSetters(context.Background(), inithook.AppName, "myapp")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// memorySpan is an in-memory stand-in of trace.Span, so no collector is needed
+type memorySpan struct {
+	trace.Span
+
+	attrs       map[attribute.Key]attribute.Value
+	events      map[string]map[attribute.Key]attribute.Value
+	code        codes.Code
+	description string
+}
+
+func newMemorySpan() *memorySpan {
+	return &memorySpan{
+		Span:   trace.SpanFromContext(context.Background()),
+		attrs:  map[attribute.Key]attribute.Value{},
+		events: map[string]map[attribute.Key]attribute.Value{},
+	}
+}
+
+func (s *memorySpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *memorySpan) AddEvent(name string, opts ...trace.EventOption) {
+	attrs := map[attribute.Key]attribute.Value{}
+	config := trace.NewEventConfig(opts...)
+	for _, kv := range config.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	s.events[name] = attrs
+}
+
+func (s *memorySpan) SetStatus(code codes.Code, description string) {
+	s.code = code
+	s.description = description
+}
+
+func TestRecordError(t *testing.T) {
+	span := newMemorySpan()
+	err := errors.WithMessage(errors.WithError(errors.New("xxx"), errors.NotFound), "wrapper")
+	otelerrors.RecordError(span, err)
+	assert.Equalf(t, "not_found(5)", span.attrs["error.type"].AsString(), "error type")
+	assert.Equalf(t, codes.Error, span.code, "status code")
+	assert.Equalf(t, "not found", span.description, "status description")
+	event := span.events["exception"]
+	assert.Equalf(t, "not_found(5)", event["exception.type"].AsString(), "exception type")
+	assert.Equalf(t, int64(404), event["error.status"].AsInt64(), "status")
+	assert.Equalf(t, "wrapper", event["error.msg"].AsString(), "msg")
+}
+
+func TestKeyValues(t *testing.T) {
+	kvs := otelerrors.KeyValues(map[string]any{"s": "s", "b": true, "i": 1, "f": 1.5, "o": []int{1}})
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	assert.Equalf(t, "s", m["s"].AsString(), "string")
+	assert.Equalf(t, true, m["b"].AsBool(), "bool")
+	assert.Equalf(t, int64(1), m["i"].AsInt64(), "int")
+	assert.Equalf(t, 1.5, m["f"].AsFloat64(), "float")
+	assert.Equalf(t, "[1]", m["o"].AsString(), "other")
+}
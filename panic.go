@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/ccmonky/log"
+)
+
+// PanicAttr used to attach the recovered panic value on error
+var PanicAttr = NewAttr[any]("panic", WithAttrDescription("recovered panic value as an attr"))
+
+// Recover recovers panic and converts it into error stored in *errp(see `FromPanic`), it must be deferred directly
+//
+// Usage:
+//
+//     func do() (err error) {
+//         defer errors.Recover(&err)
+//         // ...
+//     }
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		*errp = fromPanic(r)
+	}
+}
+
+// Safe calls fn and converts panic into error by `Recover`
+func Safe(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// Go runs fn in a new goroutine with panic recovered by `Safe`, handle will be called if fn returns error or panics,
+// if handle is nil, the error will be logged
+func Go(fn func() error, handle func(error)) {
+	go func() {
+		if err := Safe(fn); err != nil {
+			if handle != nil {
+				handle(err)
+			} else {
+				log.Error("goroutine failed", "err", err)
+			}
+		}
+	}()
+}
+
+// FromPanic converts recovered panic value r into error, the panic value is the cause if it is an error, r is stored
+// in `PanicAttr`, `Internal` is attached and current stack is captured into `StackAttr`
+func FromPanic(r any) error {
+	return fromPanic(r)
+}
+
+// SetRepanicRuntimeError specify whether to re-panic if the recovered value is a runtime.Error(e.g. nil pointer
+// dereference, index out of range), default to false
+func SetRepanicRuntimeError(repanic bool) {
+	repanicLock.Lock()
+	defer repanicLock.Unlock()
+	repanicRuntimeError = repanic
+}
+
+func fromPanic(r any) error {
+	if re, ok := r.(runtime.Error); ok {
+		repanicLock.RLock()
+		repanic := repanicRuntimeError
+		repanicLock.RUnlock()
+		if repanic {
+			panic(re)
+		}
+	}
+	cause, ok := r.(error)
+	if !ok {
+		cause = fmt.Errorf("panic: %v", r)
+	}
+	return With(cause, ErrorOption(Internal), PanicAttr.Option(r), StackAttr.Option(panicCallers()))
+}
+
+// panicCallers captures the stack of the panicking goroutine, frames above `runtime.gopanic` are skipped if exists
+func panicCallers() *stack {
+	const depth = 64
+	var pcs [depth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	var st stack = pcs[0:n]
+	for i, pc := range st {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			st = st[i+1:]
+			break
+		}
+	}
+	return &st
+}
+
+var (
+	repanicRuntimeError bool
+	repanicLock         sync.RWMutex
+)
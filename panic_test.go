@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func panicky(v any) (err error) {
+	defer errors.Recover(&err)
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	err := panicky("boom")
+	assert.Equalf(t, "panic: boom", errors.Cause(err).Error(), "cause")
+	assert.Equalf(t, "boom", errors.PanicAttr.Get(err), "panic value")
+	assert.Equalf(t, errors.Internal, errors.GetLatestMetaError(err), "internal")
+	assert.Equalf(t, 500, errors.StatusAttr.Get(err), "status")
+	stack := fmt.Sprintf("%+v", errors.StackAttr.Get(err))
+	assert.Truef(t, strings.Contains(strings.SplitN(strings.TrimSpace(stack), "\n", 2)[0], "errors_test.panicky"), "stack starts from panicking function: %s", stack)
+	assert.Falsef(t, strings.Contains(stack, "runtime.gopanic"), "stack without gopanic")
+
+	err = panicky(io.EOF)
+	assert.Equalf(t, io.EOF, errors.Cause(err), "error cause")
+	assert.Truef(t, errors.Is(err, io.EOF), "is eof")
+
+	assert.Nilf(t, errors.Safe(func() error { return nil }), "safe nil")
+	assert.Equalf(t, io.EOF, errors.Safe(func() error { return io.EOF }), "safe error")
+	err = errors.Safe(func() error {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	})
+	assert.Equalf(t, errors.Internal, errors.GetLatestMetaError(err), "safe runtime error")
+}
+
+func TestRepanicRuntimeError(t *testing.T) {
+	errors.SetRepanicRuntimeError(true)
+	defer errors.SetRepanicRuntimeError(false)
+	assert.Panicsf(t, func() {
+		errors.Safe(func() error {
+			var s []int
+			_ = s[1]
+			return nil
+		})
+	}, "repanic runtime error")
+	assert.Equalf(t, "boom", errors.PanicAttr.Get(panicky("boom")), "not runtime error")
+}
+
+func TestGo(t *testing.T) {
+	done := make(chan error)
+	errors.Go(func() error {
+		panic("boom")
+	}, func(err error) {
+		done <- err
+	})
+	err := <-done
+	assert.Equalf(t, "boom", errors.PanicAttr.Get(err), "goroutine panic")
+}
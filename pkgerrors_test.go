@@ -0,0 +1,446 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// NOTE: tests below are ported from `github.com/pkg/errors`
+
+func TestPkgNew(t *testing.T) {
+	tests := []struct {
+		err  string
+		want error
+	}{
+		{"", fmt.Errorf("")},
+		{"foo", fmt.Errorf("foo")},
+		{"foo", errors.New("foo")},
+		{"string with format specifiers: %v", errors.New("string with format specifiers: %v")},
+	}
+	for _, tt := range tests {
+		got := errors.New(tt.err)
+		assert.Equalf(t, tt.want.Error(), got.Error(), "New(%q)", tt.err)
+	}
+}
+
+func TestPkgWrapNil(t *testing.T) {
+	assert.Nilf(t, errors.Wrap(nil, "no error"), "Wrap(nil)")
+	assert.Nilf(t, errors.Wrapf(nil, "no error"), "Wrapf(nil)")
+	assert.Nilf(t, errors.WithStack(nil), "WithStack(nil)")
+	assert.Nilf(t, errors.WithMessage(nil, "no error"), "WithMessage(nil)")
+	assert.Nilf(t, errors.WithMessagef(nil, "no error"), "WithMessagef(nil)")
+}
+
+func TestPkgWrap(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	tests := []struct {
+		err     error
+		message string
+		want    string
+	}{
+		{io.EOF, "read error", "read error: EOF"},
+		{errors.Wrap(io.EOF, "read error"), "client error", "client error: read error: EOF"},
+		{errors.Wrapf(io.EOF, "read error without format specifiers"), "client error", "client error: read error without format specifiers: EOF"},
+		{errors.Wrapf(io.EOF, "read error with %d format specifier", 1), "client error", "client error: read error with 1 format specifier: EOF"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, errors.Wrap(tt.err, tt.message).Error(), "Wrap(%v, %q)", tt.err, tt.message)
+	}
+}
+
+type nilError struct{}
+
+func (nilError) Error() string { return "nil error" }
+
+func TestPkgCause(t *testing.T) {
+	x := errors.New("error")
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},                                // nil error is nil
+		{(error)(nil), nil},                       // explicit nil error is nil
+		{io.EOF, io.EOF},                          // uncaused error is unaffected
+		{errors.Wrap(io.EOF, "ignored"), io.EOF},  // caused error returns cause
+		{x, x},                                    // return from errors.New
+		{errors.WithMessage(nil, "whoops"), nil},  // WithMessage(nil) is nil
+		{errors.WithMessage(io.EOF, "w"), io.EOF}, // WithMessage(cause) is cause
+		{errors.WithStack(nil), nil},              // WithStack(nil) is nil
+		{errors.WithStack(io.EOF), io.EOF},        // WithStack(cause) is cause
+		{pkgerrors.Wrap(io.EOF, "pkg"), io.EOF},   // pkg/errors causer is honored
+		{errors.Wrap(pkgerrors.WithMessage(nilError{}, "pkg"), "ours"), nilError{}},
+	}
+	for i, tt := range tests {
+		assert.Equalf(t, tt.want, errors.Cause(tt.err), "test %d", i+1)
+	}
+	assert.Equalf(t, io.EOF, pkgerrors.Cause(errors.Wrap(io.EOF, "ours")), "pkg/errors Cause on our chain")
+}
+
+func TestPkgWrapfNil(t *testing.T) {
+	assert.Nilf(t, errors.Wrapf(nil, ""), "Wrapf(nil)")
+}
+
+func TestPkgWrapf(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	tests := []struct {
+		err     error
+		message string
+		want    string
+	}{
+		{errors.Wrapf(io.EOF, "read error without format specifiers"), "client error", "client error: read error without format specifiers: EOF"},
+		{errors.Wrapf(io.EOF, "read error with %d format specifier", 1), "client error", "client error: read error with 1 format specifier: EOF"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, errors.Wrapf(tt.err, tt.message).Error(), "Wrapf(%v, %q)", tt.err, tt.message)
+	}
+}
+
+func TestPkgErrorf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.Errorf("read error without format specifiers"), "read error without format specifiers"},
+		{errors.Errorf("read error with %d format specifier", 1), "read error with 1 format specifier"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, tt.err.Error(), "Errorf: %v", tt.err)
+	}
+	assert.Truef(t, errors.Is(errors.Errorf("read: %w", io.EOF), io.EOF), "%%w is supported")
+}
+
+func TestPkgWithMessage(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	tests := []struct {
+		err     error
+		message string
+		want    string
+	}{
+		{io.EOF, "read error", "read error: EOF"},
+		{errors.WithMessage(io.EOF, "read error"), "client error", "client error: read error: EOF"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, errors.WithMessage(tt.err, tt.message).Error(), "WithMessage(%v, %q)", tt.err, tt.message)
+		assert.Equalf(t, tt.want, errors.WithMessagef(tt.err, "%s", tt.message).Error(), "WithMessagef(%v, %q)", tt.err, tt.message)
+	}
+}
+
+func TestPkgStackTrace(t *testing.T) {
+	tests := []struct {
+		err  error
+		want []string
+	}{
+		{errors.New("ooh"), []string{
+			"github.com/ccmonky/errors_test.TestPkgStackTrace\n" +
+				"\t.+/pkgerrors_test.go:147",
+		}},
+		{errors.Wrap(errors.New("ooh"), "ahh"), []string{
+			"github.com/ccmonky/errors_test.TestPkgStackTrace\n" +
+				"\t.+/pkgerrors_test.go:151", // this is the stack of New
+		}},
+		{errors.Cause(errors.Wrap(errors.New("ooh"), "ahh")), []string{
+			"github.com/ccmonky/errors_test.TestPkgStackTrace\n" +
+				"\t.+/pkgerrors_test.go:155", // this is the stack of New
+		}},
+		{errors.WithError(errors.WithStack(io.EOF), errors.NotFound), []string{
+			"github.com/ccmonky/errors_test.TestPkgStackTrace\n" +
+				"\t.+/pkgerrors_test.go:159", // this is the oldest stack attached
+		}},
+		{func() error { return errors.Errorf("hello %s", fmt.Sprintf("world: %s", "ooh")) }(), []string{
+			`github.com/ccmonky/errors_test.TestPkgStackTrace.func1` +
+				"\n\t.+/pkgerrors_test.go:163", // this is the stack of Errorf
+			`github.com/ccmonky/errors_test.TestPkgStackTrace` +
+				"\n\t.+/pkgerrors_test.go:163", // this is the stack of Errorf's caller
+		}},
+	}
+	for i, tt := range tests {
+		x, ok := tt.err.(interface {
+			StackTrace() pkgerrors.StackTrace
+		})
+		if !assert.Truef(t, ok, "test %d: expected %#v to implement StackTrace() StackTrace", i+1, tt.err) {
+			continue
+		}
+		st := x.StackTrace()
+		for j, want := range tt.want {
+			testFormatRegexp(t, i, st[j], "%+v", want)
+		}
+	}
+	var st interface{ StackTrace() pkgerrors.StackTrace }
+	assert.Truef(t, errors.As(errors.WithError(io.EOF, errors.NotFound), &st), "chain is a stack tracer")
+	assert.Emptyf(t, st.StackTrace(), "no stack attached")
+}
+
+func TestPkgFormatNew(t *testing.T) {
+	tests := []struct {
+		error
+		format string
+		want   string
+	}{{
+		errors.New("error"),
+		"%s",
+		"error",
+	}, {
+		errors.New("error"),
+		"%v",
+		"error",
+	}, {
+		errors.New("error"),
+		"%+v",
+		"error\n" +
+			"github.com/ccmonky/errors_test.TestPkgFormatNew\n" +
+			"\t.+/pkgerrors_test.go:201",
+	}, {
+		errors.New("error"),
+		"%q",
+		`"error"`,
+	}}
+	for i, tt := range tests {
+		testFormatRegexp(t, i, tt.error, tt.format, tt.want)
+	}
+}
+
+func TestPkgFormatErrorf(t *testing.T) {
+	tests := []struct {
+		error
+		format string
+		want   string
+	}{{
+		errors.Errorf("%s", "error"),
+		"%s",
+		"error",
+	}, {
+		errors.Errorf("%s", "error"),
+		"%v",
+		"error",
+	}, {
+		errors.Errorf("%s", "error"),
+		"%+v",
+		"error\n" +
+			"github.com/ccmonky/errors_test.TestPkgFormatErrorf\n" +
+			"\t.+/pkgerrors_test.go:230",
+	}}
+	for i, tt := range tests {
+		testFormatRegexp(t, i, tt.error, tt.format, tt.want)
+	}
+}
+
+func TestPkgFormatWrap(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	tests := []struct {
+		error
+		format string
+		want   string
+	}{{
+		errors.Wrap(errors.New("error"), "error2"),
+		"%s",
+		"error2: error",
+	}, {
+		errors.Wrap(errors.New("error"), "error2"),
+		"%v",
+		"error2: error",
+	}, {
+		errors.Wrap(errors.New("error"), "error2"),
+		"%+v",
+		"error\n" +
+			"github.com/ccmonky/errors_test.TestPkgFormatWrap\n" +
+			"\t.+/pkgerrors_test.go:258",
+	}, {
+		errors.Wrap(io.EOF, "error"),
+		"%s",
+		"error: EOF",
+	}, {
+		errors.Wrap(io.EOF, "error"),
+		"%v",
+		"error: EOF",
+	}, {
+		errors.Wrap(io.EOF, "error"),
+		"%+v",
+		"EOF\n" +
+			"error\n" +
+			"github.com/ccmonky/errors_test.TestPkgFormatWrap\n" +
+			"\t.+/pkgerrors_test.go:272",
+	}, {
+		errors.Wrap(errors.Wrap(io.EOF, "error1"), "error2"),
+		"%+v",
+		"EOF\n" +
+			"error1\n" +
+			"github.com/ccmonky/errors_test.TestPkgFormatWrap\n" +
+			"\t.+/pkgerrors_test.go:279\n",
+	}, {
+		errors.Wrap(errors.New("error with space"), "context"),
+		"%q",
+		`"context: error with space"`,
+	}}
+	for i, tt := range tests {
+		testFormatRegexp(t, i, tt.error, tt.format, tt.want)
+	}
+}
+
+func TestPkgFormatWithMessage(t *testing.T) {
+	errors.SetFormatMode(errors.PkgErrors)
+	defer errors.SetFormatMode(errors.Default)
+
+	tests := []struct {
+		error
+		format string
+		want   []string
+	}{{
+		errors.WithMessage(errors.New("error"), "error2"),
+		"%s",
+		[]string{"error2: error"},
+	}, {
+		errors.WithMessage(errors.New("error"), "error2"),
+		"%v",
+		[]string{"error2: error"},
+	}, {
+		errors.WithMessage(errors.New("error"), "error2"),
+		"%+v",
+		[]string{
+			"error",
+			"github.com/ccmonky/errors_test.TestPkgFormatWithMessage\n" +
+				"\t.+/pkgerrors_test.go:312",
+			"error2"},
+	}, {
+		errors.WithMessage(io.EOF, "addition1"),
+		"%+v",
+		[]string{"EOF", "addition1"},
+	}, {
+		errors.WithMessage(errors.WithMessage(io.EOF, "addition1"), "addition2"),
+		"%+v",
+		[]string{"EOF", "addition1", "addition2"},
+	}, {
+		errors.Wrap(errors.WithMessage(io.EOF, "error1"), "error2"),
+		"%+v",
+		[]string{"EOF", "error1", "error2",
+			"github.com/ccmonky/errors_test.TestPkgFormatWithMessage\n" +
+				"\t.+/pkgerrors_test.go:328"},
+	}, {
+		errors.WithMessage(errors.Wrap(io.EOF, "error1"), "error2"),
+		"%+v",
+		[]string{"EOF", "error1",
+			"github.com/ccmonky/errors_test.TestPkgFormatWithMessage\n" +
+				"\t.+/pkgerrors_test.go:334",
+			"error2"},
+	}}
+	for i, tt := range tests {
+		testFormatCompleteCompare(t, i, tt.error, tt.format, tt.want)
+	}
+}
+
+func testFormatRegexp(t *testing.T, n int, arg interface{}, format, want string) {
+	t.Helper()
+	got := fmt.Sprintf(format, arg)
+	gotLines := strings.SplitN(got, "\n", -1)
+	wantLines := strings.SplitN(want, "\n", -1)
+	if len(wantLines) > len(gotLines) {
+		t.Errorf("test %d: wantLines(%d) > gotLines(%d):\n got: %q\nwant: %q", n+1, len(wantLines), len(gotLines), got, want)
+		return
+	}
+	for i, w := range wantLines {
+		match, err := regexp.MatchString(w, gotLines[i])
+		if assert.NoErrorf(t, err, "test %d: line %d: invalid regexp %q", n+1, i+1, w) && !match {
+			t.Errorf("test %d: line %d: fmt.Sprintf(%q, err):\n got: %q\nwant: %q", n+1, i+1, format, got, want)
+		}
+	}
+}
+
+var stackLineR = regexp.MustCompile(`\.`)
+
+// parseBlocks parses input into a slice, where:
+//   - incase entry contains a newline, its a stacktrace
+//   - incase entry contains no newline, its a solo line.
+//
+// Detecting stack boundaries only works incase the WithStack-calls are
+// to be found on the same line, thats why it is optionally here.
+func parseBlocks(input string) []string {
+	var blocks []string
+	stack := ""
+	wasStack := false
+	lines := map[string]bool{} // already found lines
+	for _, l := range strings.Split(input, "\n") {
+		isStackLine := stackLineR.MatchString(l)
+		switch {
+		case !isStackLine && wasStack:
+			blocks = append(blocks, stack, l)
+			stack = ""
+			lines = map[string]bool{}
+		case isStackLine:
+			if wasStack {
+				// Detecting two stacks after another, possible cause lines match in
+				// our tests due to WithStack(WithStack(io.EOF)) on same line.
+				if lines[l] {
+					if len(stack) == 0 {
+						panic("len of block must not be zero here")
+					}
+					blocks = append(blocks, stack)
+					stack = l
+					lines = map[string]bool{l: true}
+					continue
+				}
+				stack = stack + "\n" + l
+			} else {
+				stack = l
+			}
+			lines[l] = true
+		case !isStackLine && !wasStack:
+			blocks = append(blocks, l)
+		}
+		wasStack = isStackLine
+	}
+	if len(stack) > 0 {
+		blocks = append(blocks, stack)
+	}
+	return blocks
+}
+
+func testFormatCompleteCompare(t *testing.T, n int, arg interface{}, format string, want []string) {
+	t.Helper()
+	gotStr := fmt.Sprintf(format, arg)
+	got := parseBlocks(gotStr)
+	if !assert.Lenf(t, got, len(want), "test %d: fmt.Sprintf(%s, err) -> wrong number of blocks:\n got: %s", n+1, format, gotStr) {
+		return
+	}
+	for i := range got {
+		if strings.ContainsAny(want[i], "\n") {
+			// Match as stack
+			match, err := regexp.MatchString(want[i], got[i])
+			if assert.NoErrorf(t, err, "test %d: block %d: invalid regexp %q", n+1, i+1, want[i]) && !match {
+				t.Errorf("test %d: block %d: fmt.Sprintf(%q, err):\ngot:\n%q\nwant:\n%q\nall-got:\n%s\nall-want:\n%s\n",
+					n+1, i+1, format, got[i], want[i], gotStr, strings.Join(want, "\n"))
+			}
+		} else {
+			// Match as message
+			assert.Equalf(t, want[i], got[i], "test %d: block %d: fmt.Sprintf(%q, err)", n+1, i+1, format)
+		}
+	}
+}
+
+func TestErrorfWrap(t *testing.T) {
+	multi := errors.Errorf("a %w b %w", io.EOF, os.ErrNotExist)
+	assert.Truef(t, errors.Is(multi, io.EOF), "multiple %%w are supported")
+	assert.Truef(t, errors.Is(multi, os.ErrNotExist), "multiple %%w are supported")
+	var pathErr *os.PathError
+	assert.Truef(t, errors.As(errors.Errorf("open: %w", &os.PathError{Op: "open"}), &pathErr), "As is supported")
+	assert.Equalf(t, multi, errors.Cause(multi), "no single cause")
+	assert.Equalf(t, io.EOF, errors.Cause(errors.Errorf("read: %w", io.EOF)), "single cause")
+	assert.Falsef(t, errors.Is(errors.New("x"), io.EOF), "New wraps nothing")
+
+	_, ok := errors.RegisterMetaError(nil).(interface{ StackTrace() pkgerrors.StackTrace })
+	assert.Falsef(t, ok, "internal errors do not capture stacks")
+}
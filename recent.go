@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NewRecentErrors creates a bounded in-memory `Observer` which keeps the latest capacity occurrences of errors,
+// older occurrences are overwritten when full
+//
+// Usage:
+//
+//     recent := errors.NewRecentErrors(1024, errors.ObserveAdapt)
+//     errors.AddObserver(recent)
+//     http.Handle("/debug/errors/", http.StripPrefix("/debug/errors", errors.NewAdminHandler(recent)))
+func NewRecentErrors(capacity int, points ...ObservePoint) *RecentErrors {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	r := RecentErrors{
+		buf: make([]Occurrence, capacity),
+		now: time.Now,
+	}
+	if len(points) > 0 {
+		r.points = make(map[ObservePoint]bool, len(points))
+		for _, p := range points {
+			r.points[p] = true
+		}
+	}
+	return &r
+}
+
+// RecentErrors is a ring buffer of recent error occurrences
+type RecentErrors struct {
+	points map[ObservePoint]bool // NOTE: nil means all points
+	buf    []Occurrence
+	next   int
+	full   bool
+	now    func() time.Time
+	lock   sync.RWMutex
+}
+
+// Occurrence is the snapshot of an error when observed
+type Occurrence struct {
+	Time        time.Time    `json:"time"`
+	Point       ObservePoint `json:"point"`
+	MetaID      string       `json:"meta_id"`
+	Code        string       `json:"code"`
+	Status      int          `json:"status"`
+	Message     string       `json:"message"`
+	Fingerprint string       `json:"fingerprint"`
+	Stack       string       `json:"stack,omitempty"`
+}
+
+// RecentQuery is the filter of `RecentErrors.Query`, zero fields are ignored
+type RecentQuery struct {
+	// Code matches the code of the latest meta
+	Code string
+
+	// MetaID matches the id of the latest meta
+	MetaID string
+
+	// Since matches occurrences at or after it
+	Since time.Time
+
+	// Until matches occurrences before it
+	Until time.Time
+
+	// Limit is the max number of occurrences returned
+	Limit int
+}
+
+// Observe implement `Observer`
+func (r *RecentErrors) Observe(point ObservePoint, err error) {
+	if err == nil {
+		return
+	}
+	if r.points != nil && !r.points[point] {
+		return
+	}
+	o := Occurrence{
+		Time:        r.now(),
+		Point:       point,
+		Status:      StatusAttr.Get(err),
+		Message:     err.Error(),
+		Fingerprint: FingerprintAttr.Get(err),
+	}
+	if m := MetaAttr.Get(err); m != nil {
+		o.MetaID, o.Code = m.ID(), m.code
+	}
+	if st := originStack(err); st != nil {
+		o.Stack = fmt.Sprintf("%+v", st)
+	}
+	r.lock.Lock()
+	r.buf[r.next] = o
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.lock.Unlock()
+}
+
+// Query returns occurrences which match q, newest first
+func (r *RecentErrors) Query(q RecentQuery) []Occurrence {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	n := r.next
+	if r.full {
+		n = len(r.buf)
+	}
+	var result []Occurrence
+	for i := 0; i < n; i++ {
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+		o := r.buf[(r.next-1-i+len(r.buf))%len(r.buf)]
+		if q.Code != "" && o.Code != q.Code {
+			continue
+		}
+		if q.MetaID != "" && o.MetaID != q.MetaID {
+			continue
+		}
+		if !q.Since.IsZero() && o.Time.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !o.Time.Before(q.Until) {
+			continue
+		}
+		result = append(result, o)
+	}
+	return result
+}
+
+// Len returns the number of occurrences kept
+func (r *RecentErrors) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.full {
+		return len(r.buf)
+	}
+	return r.next
+}
+
+// Reset clear all occurrences
+func (r *RecentErrors) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.buf = make([]Occurrence, len(r.buf))
+	r.next, r.full = 0, false
+}
+
+var (
+	_ Observer = (*RecentErrors)(nil)
+)
@@ -0,0 +1,128 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentErrors(t *testing.T) {
+	recent := errors.NewRecentErrors(3, errors.ObserveAdapt)
+	remove := errors.AddObserver(recent)
+	defer remove()
+
+	start := time.Now()
+	errors.Adapt(errors.WithStack(errors.New("a")), errors.NotFound)
+	errors.WriteHTTP(httptest.NewRecorder(), errors.New("ignored"))
+	for _, msg := range []string{"b", "c", "d"} {
+		errors.Adapt(errors.New(msg), errors.Unavailable)
+	}
+	assert.Equalf(t, 3, recent.Len(), "bounded")
+
+	occurrences := recent.Query(errors.RecentQuery{})
+	assert.Lenf(t, occurrences, 3, "all kept")
+	assert.Equalf(t, "d", occurrences[0].Message[:1], "newest first")
+	assert.Equalf(t, "c", occurrences[1].Message[:1], "newest first")
+	assert.Equalf(t, "b", occurrences[2].Message[:1], "a is overwritten")
+	assert.Equalf(t, errors.ObserveAdapt, occurrences[0].Point, "point")
+	assert.Equalf(t, "unavailable(14)", occurrences[0].Code, "code")
+	assert.Equalf(t, errors.MetaAttr.Get(errors.Unavailable).ID(), occurrences[0].MetaID, "meta id")
+	assert.Equalf(t, 503, occurrences[0].Status, "status")
+	assert.Lenf(t, occurrences[0].Fingerprint, 32, "fingerprint")
+	assert.Containsf(t, occurrences[0].Stack, "recent_test.go:23", "stack of New")
+
+	assert.Lenf(t, recent.Query(errors.RecentQuery{Limit: 2}), 2, "limit")
+	assert.Lenf(t, recent.Query(errors.RecentQuery{Code: "not_found(5)"}), 0, "code")
+	assert.Lenf(t, recent.Query(errors.RecentQuery{Since: start}), 3, "since")
+	assert.Lenf(t, recent.Query(errors.RecentQuery{Until: start}), 0, "until")
+	assert.Lenf(t, recent.Query(errors.RecentQuery{Since: time.Now().Add(time.Second)}), 0, "since future")
+
+	recent.Reset()
+	assert.Equalf(t, 0, recent.Len(), "reset")
+	errors.Adapt(errors.WithStack(errors.New("a")), errors.NotFound)
+	occurrences = recent.Query(errors.RecentQuery{MetaID: errors.MetaAttr.Get(errors.NotFound).ID()})
+	if assert.Lenf(t, occurrences, 1, "meta id") {
+		assert.Containsf(t, occurrences[0].Stack, "errors_test.TestRecentErrors", "stack of WithStack")
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	recent := errors.NewRecentErrors(10)
+	recent.Observe(errors.ObserveAdapt, errors.WithError(errors.New("<a>"), errors.NotFound))
+	recent.Observe(errors.ObserveAdapt, errors.WithError(errors.New("b"), errors.Unavailable))
+	handler := errors.NewAdminHandler(recent)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/recent?code=not_found(5)")
+	assert.Equalf(t, http.StatusOK, w.Code, "recent")
+	assert.Equalf(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), "json")
+	var occurrences []errors.Occurrence
+	assert.Nilf(t, json.Unmarshal(w.Body.Bytes(), &occurrences), "unmarshal recent")
+	if assert.Lenf(t, occurrences, 1, "code") {
+		assert.Equalf(t, "not_found(5)", occurrences[0].Code, "code")
+	}
+	w = get("/recent?since=1m&limit=5")
+	assert.Nilf(t, json.Unmarshal(w.Body.Bytes(), &occurrences), "unmarshal recent")
+	assert.Lenf(t, occurrences, 2, "since & limit")
+	w = get("/recent?until=2000-01-01T00:00:00Z")
+	assert.Equalf(t, "[]\n", w.Body.String(), "empty array instead of null")
+	w = get("/recent?limit=x")
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "invalid limit")
+
+	w = get("/catalog")
+	var catalog errors.Catalog
+	assert.Nilf(t, json.Unmarshal(w.Body.Bytes(), &catalog), "unmarshal catalog")
+	assert.Equalf(t, errors.NewCatalog("").Entries, catalog.Entries, "catalog")
+
+	w = get("/attrs")
+	var attrs map[string]map[string]any
+	assert.Nilf(t, json.Unmarshal(w.Body.Bytes(), &attrs), "unmarshal attrs")
+	assert.Lenf(t, attrs, len(errors.AllAttrs()), "attrs")
+
+	w = get("/")
+	assert.Equalf(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"), "html")
+	assert.Containsf(t, w.Body.String(), "&lt;a&gt;", "escaped")
+	assert.Containsf(t, w.Body.String(), errors.MetaAttr.Get(errors.DataLoss).ID(), "catalog")
+	assert.Containsf(t, w.Body.String(), "<td>severity</td>", "attrs")
+
+	assert.Equalf(t, http.StatusNotFound, get("/xxx").Code, "not found")
+	w = httptest.NewRecorder()
+	errors.NewAdminHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/recent", nil))
+	assert.Equalf(t, "[]\n", w.Body.String(), "nil recent")
+}
+
+func TestRecentErrorsWithOtherObservers(t *testing.T) {
+	recent := errors.NewRecentErrors(10, errors.ObserveAdapt)
+	mo := errors.NewMetricsObserver(errors.ObserveAdapt)
+	defer errors.AddObserver(recent)()
+	defer errors.AddObserver(mo)()
+	errors.Adapt(errors.New("xxx"), errors.NotFound)
+	assert.Equalf(t, 1, recent.Len(), "recent observed")
+	assert.Equalf(t, 1, len(mo.Counters()), "metrics observed too")
+}
+
+func TestAdminHandlerNotObserved(t *testing.T) {
+	recent := errors.NewRecentErrors(10)
+	defer errors.AddObserver(recent)()
+	errors.SetObserveWithError(true)
+	defer errors.SetObserveWithError(false)
+	handler := errors.NewAdminHandler(recent)
+	for _, path := range []string{"/xxx", "/recent?limit=x", "/?since=x"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.NotEqualf(t, http.StatusOK, w.Code, "%s: failed", path)
+	}
+	assert.Equalf(t, 0, recent.Len(), "failures of admin not recorded")
+	errors.WriteHTTP(httptest.NewRecorder(), errors.NotFound)
+	assert.Equalf(t, 1, recent.Len(), "WriteHTTP observed")
+}
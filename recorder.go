@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Span is the minimal tracing span abstraction used by `Recorder`, so that this package does not depend on any
+// tracing sdk, see subpackage `otelerrors` for the OpenTelemetry adapter
+type Span interface {
+	// SetAttributes set attributes on span
+	SetAttributes(attrs map[string]any)
+
+	// AddEvent add an event with attributes on span
+	AddEvent(name string, attrs map[string]any)
+
+	// SetError mark span as failed with description
+	SetError(description string)
+}
+
+// Recorder records error on span
+type Recorder interface {
+	RecordError(span Span, err error)
+}
+
+var (
+	// SpanErrorTypeKey is the span attribute key of error type, the value is meta code or cause type if no meta
+	SpanErrorTypeKey = "error.type"
+
+	// SpanEventName is the name of span event which records error
+	SpanEventName = "exception"
+
+	// SpanEventAttrPrefix is the prefix of error attrs in span event
+	SpanEventAttrPrefix = "error."
+)
+
+// NewRecorder creates a `Recorder` which flattens err by `Attrs.Map` if attrs specified, otherwise by `Map`
+func NewRecorder(attrs ...AttrInterface) Recorder {
+	return &recorder{
+		attrs: NewAttrs(attrs...),
+	}
+}
+
+// RecordError records err on span by default recorder, which sets `error.type` attribute and status on span, and adds
+// an `exception` event with type, message, stacktrace, status and all flattened attrs
+func RecordError(span Span, err error) {
+	defaultRecorder.RecordError(span, err)
+}
+
+type recorder struct {
+	attrs Attrs
+}
+
+// RecordError implement `Recorder`
+func (r *recorder) RecordError(span Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	var m map[string]any
+	if len(r.attrs) > 0 {
+		m = r.attrs.Map(err)
+	} else {
+		m = Map(err)
+	}
+	errType := GetCode(err)
+	if errType == "" {
+		errType = reflect.TypeOf(Cause(err)).String()
+	}
+	attrs := make(map[string]any, len(m)+4)
+	for k, v := range m {
+		if v, ok := spanValue(v); ok {
+			attrs[SpanEventAttrPrefix+k] = v
+		}
+	}
+	attrs["exception.type"] = errType
+	attrs["exception.message"] = err.Error()
+	attrs[SpanEventAttrPrefix+StatusAttr.Name()] = StatusAttr.Get(err)
+	if st := originStack(err); st != nil {
+		attrs["exception.stacktrace"] = fmt.Sprintf("%+v", st)
+	}
+	span.SetAttributes(map[string]any{
+		SpanErrorTypeKey: errType,
+	})
+	span.AddEvent(SpanEventName, attrs)
+	message := GetMessage(err)
+	if message == "" {
+		message = err.Error()
+	}
+	span.SetError(message)
+}
+
+// spanValue converts v into span attribute value, nested errors, metas, contexts and stacks are ignored since they are
+// flattened or recorded separately
+func spanValue(v any) (any, bool) {
+	switch tv := v.(type) {
+	case nil, error, *Meta, context.Context, *stack:
+		return nil, false
+	case string, bool, int, int64, float64:
+		return tv, true
+	case fmt.Stringer:
+		return tv.String(), true
+	default:
+		return fmt.Sprint(tv), true
+	}
+}
+
+var defaultRecorder = NewRecorder()
+
+var (
+	_ Recorder = (*recorder)(nil)
+)
@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySpan struct {
+	attrs  map[string]any
+	events map[string]map[string]any
+	status string
+}
+
+func newMemorySpan() *memorySpan {
+	return &memorySpan{
+		attrs:  map[string]any{},
+		events: map[string]map[string]any{},
+	}
+}
+
+func (s *memorySpan) SetAttributes(attrs map[string]any) {
+	for k, v := range attrs {
+		s.attrs[k] = v
+	}
+}
+
+func (s *memorySpan) AddEvent(name string, attrs map[string]any) {
+	s.events[name] = attrs
+}
+
+func (s *memorySpan) SetError(description string) {
+	s.status = description
+}
+
+func TestRecordError(t *testing.T) {
+	span := newMemorySpan()
+	err := errors.Wrap(errors.WithError(errors.New("xxx"), errors.NotFound), "wrapper")
+	errors.RecordError(span, err)
+	assert.Equalf(t, "not_found(5)", span.attrs["error.type"], "error type")
+	assert.Equalf(t, "not found", span.status, "status")
+	event := span.events["exception"]
+	assert.Equalf(t, "not_found(5)", event["exception.type"], "exception type")
+	assert.Equalf(t, err.Error(), event["exception.message"], "exception message")
+	assert.Equalf(t, 404, event["error.status"], "status")
+	assert.Equalf(t, "wrapper", event["error.msg"], "msg")
+	assert.Equalf(t, "github.com/ccmonky/errors", event["error.meta.source"], "meta source")
+	assert.Truef(t, strings.Contains(event["exception.stacktrace"].(string), "TestRecordError"), "stacktrace")
+	assert.NotContainsf(t, event, "error.error", "nested error flattened")
+	assert.NotContainsf(t, event, "error.stack", "stack recorded as stacktrace")
+
+	span = newMemorySpan()
+	errors.NewRecorder(errors.MessageAttr).RecordError(span, errors.WithMessage(errors.New("xxx"), "wrapper"))
+	assert.Equalf(t, "*errors.fundamental", span.attrs["error.type"], "error type without meta")
+	assert.Equalf(t, "xxx:msg={wrapper}", span.status, "status without meta")
+	assert.Equalf(t, "wrapper", span.events["exception"]["error.msg"], "specified attrs")
+	assert.Equalf(t, 500, span.events["exception"]["error.status"], "default status")
+
+	span = newMemorySpan()
+	errors.RecordError(span, nil)
+	assert.Equalf(t, 0, len(span.events), "nil error")
+}
@@ -0,0 +1,316 @@
+package errors
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ccmonky/log"
+)
+
+// NewSampler creates a `Sampler` which decides whether an error should be logged/exported, errors are grouped by key
+// (default to the latest meta id, see `SampleByMetaID` and `SampleByFingerprint`), each key has its own limiter created
+// by strategy(see `TokenBucket` and `FirstNThenEveryM`), keys idle for `DefaultSampleTTL` are evicted and at most
+// `DefaultSampleMaxKeys` keys are kept(the least recently sampled one is evicted), so that the memory is bounded even if
+// keys are high cardinality(e.g. `SampleByFingerprint`)
+//
+// Usage:
+//
+//     sampler := errors.NewSampler(errors.FirstNThenEveryM(10, 100, time.Minute))
+//     stop := sampler.Start(time.Minute, nil) // log suppressed counts every minute
+//     defer stop()
+//     if sampler.Sample(err) {
+//         log.Error("call upstream failed", "err", err)
+//     }
+func NewSampler(strategy SamplingStrategy, opts ...SamplerOption) *Sampler {
+	s := Sampler{
+		strategy: strategy,
+		key:      SampleByMetaID,
+		now:      time.Now,
+		ttl:      DefaultSampleTTL,
+		maxKeys:  DefaultSampleMaxKeys,
+		states:   make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return &s
+}
+
+// defaults of sampler
+var (
+	DefaultSampleTTL     = 10 * time.Minute
+	DefaultSampleMaxKeys = 10000
+)
+
+// SamplerOption sampler control option
+type SamplerOption func(*Sampler)
+
+// WithSampleKey specify the function which returns the key an error is sampled by
+func WithSampleKey(fn func(error) string) SamplerOption {
+	return func(s *Sampler) {
+		s.key = fn
+	}
+}
+
+// WithSampleTTL specify the duration after which an idle key is evicted, 0 means never
+func WithSampleTTL(ttl time.Duration) SamplerOption {
+	return func(s *Sampler) {
+		s.ttl = ttl
+	}
+}
+
+// WithSampleMaxKeys specify the max number of keys, the least recently sampled key is evicted if exceeded, 0 means
+// unlimited
+func WithSampleMaxKeys(n int) SamplerOption {
+	return func(s *Sampler) {
+		s.maxKeys = n
+	}
+}
+
+// WithSamplerClock specify the clock of sampler, mainly used in test
+func WithSamplerClock(now func() time.Time) SamplerOption {
+	return func(s *Sampler) {
+		s.now = now
+	}
+}
+
+// SampleByMetaID samples errors by the latest meta id
+func SampleByMetaID(err error) string {
+	if m := MetaAttr.Get(err); m != nil {
+		return m.ID()
+	}
+	return ""
+}
+
+// SampleByFingerprint samples errors by `FingerprintAttr`
+func SampleByFingerprint(err error) string {
+	return FingerprintAttr.Get(err)
+}
+
+// SamplingStrategy creates a limiter for each sample key
+type SamplingStrategy interface {
+	NewLimiter() SampleLimiter
+}
+
+// SampleLimiter decides whether an occurrence at now is allowed, limiter is guarded by sampler, so it need not to be
+// concurrent safe
+type SampleLimiter interface {
+	Allow(now time.Time) bool
+}
+
+// SamplingStrategyFunc is a function implements `SamplingStrategy`
+type SamplingStrategyFunc func() SampleLimiter
+
+// NewLimiter implement `SamplingStrategy`
+func (fn SamplingStrategyFunc) NewLimiter() SampleLimiter {
+	return fn()
+}
+
+// TokenBucket allows burst errors at once and then rate errors per second for each key
+func TokenBucket(rate float64, burst int) SamplingStrategy {
+	return SamplingStrategyFunc(func() SampleLimiter {
+		return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+	})
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) Allow(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// FirstNThenEveryM allows the first n errors and then every mth error for each key, the count will be reset after window
+// since the first error of the window if window > 0
+func FirstNThenEveryM(n, m int, window time.Duration) SamplingStrategy {
+	return SamplingStrategyFunc(func() SampleLimiter {
+		return &firstNThenEveryM{n: n, m: m, window: window}
+	})
+}
+
+type firstNThenEveryM struct {
+	n, m   int
+	window time.Duration
+	start  time.Time
+	count  int
+}
+
+func (f *firstNThenEveryM) Allow(now time.Time) bool {
+	if f.window > 0 && (f.start.IsZero() || now.Sub(f.start) >= f.window) {
+		f.start = now
+		f.count = 0
+	}
+	f.count++
+	if f.count <= f.n {
+		return true
+	}
+	return f.m > 0 && (f.count-f.n)%f.m == 0
+}
+
+// Sampler samples errors by key
+type Sampler struct {
+	strategy SamplingStrategy
+	key      func(error) string
+	now      func() time.Time
+	ttl      time.Duration
+	maxKeys  int
+	states   map[string]*list.Element
+	lru      *list.List // NOTE: of *sampleState, the front is the most recently sampled one
+	lock     sync.Mutex
+}
+
+type sampleState struct {
+	key        string
+	limiter    SampleLimiter
+	allowed    uint64
+	suppressed uint64
+	seen       time.Time
+}
+
+// SampleSummary is the sampled counts of a key since last summary
+type SampleSummary struct {
+	Key        string `json:"key"`
+	Allowed    uint64 `json:"allowed"`
+	Suppressed uint64 `json:"suppressed"`
+}
+
+// Sample returns true if err should be logged/exported, nil err always returns false
+//
+// NOTE: counts of evicted keys are dropped and will not be reported by `Summary`
+func (s *Sampler) Sample(err error) bool {
+	if err == nil {
+		return false
+	}
+	key := s.key(err)
+	now := s.now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evictIdle(now)
+	var state *sampleState
+	if elem, ok := s.states[key]; ok {
+		state = elem.Value.(*sampleState)
+		s.lru.MoveToFront(elem)
+	} else {
+		if s.maxKeys > 0 && s.lru.Len() >= s.maxKeys {
+			s.evict(s.lru.Back())
+		}
+		state = &sampleState{key: key, limiter: s.strategy.NewLimiter()}
+		s.states[key] = s.lru.PushFront(state)
+	}
+	state.seen = now
+	if state.limiter.Allow(now) {
+		state.allowed++
+		return true
+	}
+	state.suppressed++
+	return false
+}
+
+// Len returns the number of keys tracked
+func (s *Sampler) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lru.Len()
+}
+
+// evictIdle evicts keys not sampled for ttl
+func (s *Sampler) evictIdle(now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+	for elem := s.lru.Back(); elem != nil && now.Sub(elem.Value.(*sampleState).seen) >= s.ttl; elem = s.lru.Back() {
+		s.evict(elem)
+	}
+}
+
+func (s *Sampler) evict(elem *list.Element) {
+	s.lru.Remove(elem)
+	delete(s.states, elem.Value.(*sampleState).key)
+}
+
+// Summary returns the counts of keys which have suppressed errors since last summary(sorted by key), and reset counts,
+// idle keys are evicted
+func (s *Sampler) Summary() []SampleSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evictIdle(s.now())
+	var summaries []SampleSummary
+	for key, elem := range s.states {
+		state := elem.Value.(*sampleState)
+		if state.suppressed > 0 {
+			summaries = append(summaries, SampleSummary{
+				Key:        key,
+				Allowed:    state.allowed,
+				Suppressed: state.suppressed,
+			})
+		}
+		state.allowed, state.suppressed = 0, 0
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries
+}
+
+// Start emits `Summary` to report every interval until stop called, the default report logs each summary,
+// the summaries will not be reported if empty
+func (s *Sampler) Start(interval time.Duration, report func([]SampleSummary)) (stop func()) {
+	if report == nil {
+		report = logSampleSummaries
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if summaries := s.Summary(); len(summaries) > 0 {
+					report(summaries)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// Observer returns an `Observer` which only forwards sampled errors to next, it can be installed by `AddObserver`
+// together with the unsampled ones
+func (s *Sampler) Observer(next Observer) Observer {
+	return ObserverFunc(func(point ObservePoint, err error) {
+		if s.Sample(err) {
+			next.Observe(point, err)
+		}
+	})
+}
+
+func logSampleSummaries(summaries []SampleSummary) {
+	for _, summary := range summaries {
+		log.Info("errors suppressed", "key", summary.Key, "allowed", summary.Allowed, "suppressed", summary.Suppressed)
+	}
+}
@@ -0,0 +1,145 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestSamplerFirstNThenEveryM(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	sampler := errors.NewSampler(errors.FirstNThenEveryM(2, 3, time.Minute), errors.WithSamplerClock(clock.Now))
+	unavailable := errors.WithError(errors.New("xxx"), errors.Unavailable)
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if sampler.Sample(unavailable) {
+			allowed = append(allowed, i)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 5, 8}, allowed)
+	assert.Truef(t, sampler.Sample(errors.WithError(errors.New("xxx"), errors.NotFound)), "keys are independent")
+	assert.False(t, sampler.Sample(nil))
+
+	assert.Equal(t, []errors.SampleSummary{
+		{Key: errors.MetaAttr.Get(errors.Unavailable).ID(), Allowed: 4, Suppressed: 6},
+	}, sampler.Summary())
+	assert.Nilf(t, sampler.Summary(), "counts reset after summary")
+
+	clock.Add(time.Minute)
+	assert.Truef(t, sampler.Sample(unavailable), "window reset")
+	assert.True(t, sampler.Sample(unavailable))
+	assert.False(t, sampler.Sample(unavailable))
+}
+
+func TestSamplerTokenBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	sampler := errors.NewSampler(errors.TokenBucket(2, 3), errors.WithSamplerClock(clock.Now))
+	err := errors.WithError(errors.New("xxx"), errors.Unavailable)
+	count := func(n int) int {
+		allowed := 0
+		for i := 0; i < n; i++ {
+			if sampler.Sample(err) {
+				allowed++
+			}
+		}
+		return allowed
+	}
+	assert.Equalf(t, 3, count(10), "burst")
+	clock.Add(time.Second)
+	assert.Equalf(t, 2, count(10), "rate")
+	clock.Add(time.Hour)
+	assert.Equalf(t, 3, count(10), "tokens never exceed burst")
+}
+
+func TestSamplerKey(t *testing.T) {
+	sampler := errors.NewSampler(errors.FirstNThenEveryM(1, 0, 0), errors.WithSampleKey(func(err error) string {
+		return errors.Fingerprint(err, errors.FingerprintMessage())
+	}))
+	var allowed int
+	for i := 0; i < 3; i++ {
+		for _, msg := range []string{"a", "b"} {
+			if sampler.Sample(errors.WithError(errors.New(msg), errors.Unavailable)) {
+				allowed++
+			}
+		}
+	}
+	assert.Equalf(t, 2, allowed, "a & b have different fingerprints")
+	assert.Len(t, sampler.Summary(), 2)
+}
+
+func TestSamplerStart(t *testing.T) {
+	sampler := errors.NewSampler(errors.FirstNThenEveryM(0, 0, 0))
+	sampler.Sample(errors.New("xxx"))
+	reported := make(chan []errors.SampleSummary, 1)
+	stop := sampler.Start(time.Millisecond, func(summaries []errors.SampleSummary) {
+		reported <- summaries
+	})
+	defer stop()
+	select {
+	case summaries := <-reported:
+		assert.Equal(t, []errors.SampleSummary{{Key: "", Suppressed: 1}}, summaries)
+	case <-time.After(time.Second):
+		t.Fatal("summary not reported")
+	}
+	stop()
+}
+
+func TestSamplerObserver(t *testing.T) {
+	var observed int
+	sampler := errors.NewSampler(errors.FirstNThenEveryM(1, 0, 0))
+	errors.SetObserver(sampler.Observer(errors.ObserverFunc(func(point errors.ObservePoint, err error) {
+		observed++
+	})))
+	defer errors.SetObserver(nil)
+	for i := 0; i < 5; i++ {
+		errors.Adapt(errors.New("xxx"), errors.Unavailable)
+	}
+	assert.Equal(t, 1, observed)
+}
+
+func TestSamplerEviction(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	byMessage := errors.WithSampleKey(func(err error) string { return err.Error() })
+	sampler := errors.NewSampler(errors.FirstNThenEveryM(1, 0, 0), byMessage, errors.WithSamplerClock(clock.Now),
+		errors.WithSampleMaxKeys(100), errors.WithSampleTTL(time.Minute))
+	for i := 0; i < 1000; i++ {
+		assert.Truef(t, sampler.Sample(fmt.Errorf("err-%d", i)), "first of key %d", i)
+	}
+	assert.Equalf(t, 100, sampler.Len(), "bounded by max keys")
+	assert.Falsef(t, sampler.Sample(fmt.Errorf("err-999")), "recent key kept")
+	assert.Truef(t, sampler.Sample(fmt.Errorf("err-0")), "least recently sampled key evicted")
+	assert.Equalf(t, 100, sampler.Len(), "bounded by max keys")
+
+	clock.Add(30 * time.Second)
+	sampler.Sample(fmt.Errorf("err-999"))
+	clock.Add(30 * time.Second)
+	assert.Equalf(t, []errors.SampleSummary{{Key: "err-999", Allowed: 1, Suppressed: 2}}, sampler.Summary(), "idle keys evicted on summary")
+	assert.Equalf(t, 1, sampler.Len(), "only the active key kept")
+	clock.Add(time.Minute)
+	assert.Truef(t, sampler.Sample(fmt.Errorf("err-0")), "new key")
+	assert.Equalf(t, 1, sampler.Len(), "idle keys evicted on sample")
+
+	unbounded := errors.NewSampler(errors.FirstNThenEveryM(1, 0, 0), byMessage, errors.WithSamplerClock(clock.Now),
+		errors.WithSampleMaxKeys(0), errors.WithSampleTTL(0))
+	for i := 0; i < 1000; i++ {
+		unbounded.Sample(fmt.Errorf("err-%d", i))
+	}
+	clock.Add(time.Hour)
+	unbounded.Summary()
+	assert.Equalf(t, 1000, unbounded.Len(), "eviction disabled")
+}
@@ -0,0 +1,226 @@
+// Package sentryerrors exports errors to Sentry(or any compatible server) as event envelopes without requiring the
+// Sentry SDK, the error chain is expanded into the exception list, stack frames, tags and extras.
+package sentryerrors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ccmonky/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Event is the Sentry event payload
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Exception   Exceptions        `json:"exception"`
+}
+
+// Exceptions is the exception interface of Sentry event, the last value is the most recent one
+type Exceptions struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is a single exception of Sentry event
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace is the stack trace of exception, frames are ordered from the oldest to the newest
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a single stack frame
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// NewEvent creates Sentry event from err:
+// 1. exception list is built from `errors.GetAllErrors`, MetaErrors use code as type and message as value
+// 2. stack frames come from pkg/errors compatible `StackTrace()` of each error, i.e. the origin stack of the chain
+// 3. tags come from the latest meta code, source, app and status
+// 4. extras come from other attrs with *string key(see `errors.Map`)
+// 5. level follows `errors.MaxSeverity` and fingerprint is `errors.FingerprintAttr`
+//
+// returns nil if err is nil
+func NewEvent(err error) *Event {
+	if err == nil {
+		return nil
+	}
+	ev := Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       level(errors.MaxSeverity(err)),
+		Fingerprint: []string{errors.FingerprintAttr.Get(err)},
+		Tags: map[string]string{
+			"status": strconv.Itoa(errors.StatusAttr.Get(err)),
+		},
+		Extra: map[string]any{},
+	}
+	if m := errors.MetaAttr.Get(err); m != nil {
+		ev.Tags[errors.MetaAttrAppFieldName] = m.App()
+		ev.Tags[errors.MetaAttrSourceFieldName] = m.Source()
+		ev.Tags[errors.MetaAttrCodeFieldName] = m.Code()
+	}
+	for k, v := range errors.Map(err) {
+		if _, ok := ev.Tags[k]; ok || k == errors.StatusAttr.Name() || k == errors.MetaAttrMessageFieldName {
+			continue
+		}
+		switch v.(type) {
+		case error, *errors.Meta, context.Context, stackTracer:
+			continue
+		case string, bool, int, int64, float64:
+			ev.Extra[k] = v
+		default:
+			ev.Extra[k] = fmt.Sprint(v)
+		}
+	}
+	for _, e := range errors.GetAllErrors(err) {
+		if e == errors.Empty() { // NOTE: the cause of MetaErrors
+			continue
+		}
+		ev.Exception.Values = append(ev.Exception.Values, exception(e))
+	}
+	if len(ev.Exception.Values) == 0 { // NOTE: err is a MetaError itself
+		ev.Exception.Values = append(ev.Exception.Values, exception(err))
+	}
+	ev.Exception.Values[0].Stacktrace = stacktrace(err) // NOTE: the earliest stack of the chain belongs to the cause
+	return &ev
+}
+
+func exception(err error) Exception {
+	if me, ok := err.(errors.MetaError); ok && me.Code() != "" {
+		return Exception{
+			Type:       me.Code(),
+			Value:      me.Message(),
+			Module:     me.Source(),
+			Stacktrace: stacktrace(err),
+		}
+	}
+	t := reflect.TypeOf(err)
+	ex := Exception{
+		Type:  t.String(),
+		Value: err.Error(),
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	ex.Module = t.PkgPath()
+	return ex
+}
+
+func stacktrace(err error) *Stacktrace {
+	var st pkgerrors.StackTrace
+	if tracer, ok := err.(stackTracer); ok {
+		st = tracer.StackTrace()
+	}
+	if len(st) == 0 {
+		return nil
+	}
+	pcs := make([]uintptr, len(st))
+	for i, f := range st {
+		pcs[i] = uintptr(f)
+	}
+	var frames []Frame
+	rframes := runtime.CallersFrames(pcs)
+	for {
+		rf, more := rframes.Next()
+		if rf.Function != "" {
+			module, function := splitFunction(rf.Function)
+			frames = append(frames, Frame{
+				Function: function,
+				Module:   module,
+				Filename: filename(rf.File),
+				AbsPath:  rf.File,
+				Lineno:   rf.Line,
+				InApp:    inApp(module),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 { // NOTE: sentry expects the oldest frame first
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return &Stacktrace{Frames: frames}
+}
+
+// splitFunction splits `github.com/a/b.(*T).F` into `github.com/a/b` and `(*T).F`
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
+
+func filename(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// inApp reports whether the module is not a standard library package
+func inApp(module string) bool {
+	first := strings.SplitN(module, "/", 2)[0]
+	return strings.Contains(first, ".")
+}
+
+func level(s errors.Severity) string {
+	switch {
+	case s <= errors.SeverityDebug:
+		return "debug"
+	case s == errors.SeverityInfo:
+		return "info"
+	case s == errors.SeverityWarning:
+		return "warning"
+	case s == errors.SeverityError:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+func newEventID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil { // NOTE: fall back to a unique id of this process
+		binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixNano()))
+		binary.BigEndian.PutUint64(id[8:], atomic.AddUint64(&eventIDSeq, 1))
+	}
+	return hex.EncodeToString(id[:])
+}
+
+var eventIDSeq uint64
+
+// stackTracer is the stack interface of pkg/errors
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
@@ -0,0 +1,161 @@
+package sentryerrors_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/ccmonky/errors/sentryerrors"
+	"github.com/ccmonky/inithook"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	err := inithook.ExecuteAttrSetters(context.Background(), inithook.AppName, "myapp")
+	if err != nil {
+		panic(err)
+	}
+}
+
+var userAttr = errors.NewAttr[string]("user")
+
+func TestNewEvent(t *testing.T) {
+	err := errors.WithError(errors.WithStack(io.EOF), errors.NotFound)
+	err = errors.With(err, userAttr.Option("alice"))
+	ev := sentryerrors.NewEvent(err)
+	assert.Lenf(t, ev.EventID, 32, "event id")
+	assert.Equalf(t, "go", ev.Platform, "platform")
+	assert.Equalf(t, "info", ev.Level, "level of not found")
+	assert.Equalf(t, []string{errors.FingerprintAttr.Get(err)}, ev.Fingerprint, "fingerprint")
+	assert.Equalf(t, map[string]string{
+		"meta.app":    "myapp",
+		"meta.source": "github.com/ccmonky/errors",
+		"meta.code":   "not_found(5)",
+		"status":      "404",
+	}, ev.Tags, "tags")
+	assert.Equalf(t, "alice", ev.Extra["user"], "extra")
+	assert.NotContainsf(t, ev.Extra, "stack", "stack is not extra")
+	assert.Lenf(t, ev.Exception.Values, 2, "cause & latest meta")
+	cause := ev.Exception.Values[0]
+	assert.Equalf(t, "*errors.errorString", cause.Type, "cause type")
+	assert.Equalf(t, "EOF", cause.Value, "cause value")
+	assert.Equalf(t, "errors", cause.Module, "cause module")
+	if assert.NotNilf(t, cause.Stacktrace, "stack of WithStack") {
+		frames := cause.Stacktrace.Frames
+		last := frames[len(frames)-1]
+		assert.Equalf(t, "github.com/ccmonky/errors/sentryerrors_test", last.Module, "frame module")
+		assert.Regexpf(t, `^TestNewEvent event_test.go:\d+$`, fmt.Sprintf("%s %s:%d", last.Function, last.Filename, last.Lineno), "frame of WithStack")
+		assert.Truef(t, last.InApp, "test frame in app")
+		assert.Falsef(t, frames[0].InApp, "runtime frame not in app")
+	}
+	latest := ev.Exception.Values[1]
+	assert.Equalf(t, "not_found(5)", latest.Type, "latest type")
+	assert.Equalf(t, "not found", latest.Value, "latest value")
+	assert.Equalf(t, "github.com/ccmonky/errors", latest.Module, "latest module")
+	assert.Nilf(t, latest.Stacktrace, "meta has no stack")
+}
+
+func TestNewEventLevel(t *testing.T) {
+	ev := sentryerrors.NewEvent(errors.WithError(io.EOF, errors.DataLoss))
+	assert.Equalf(t, "fatal", ev.Level, "level of data loss")
+	ev = sentryerrors.NewEvent(fmt.Errorf("plain"))
+	assert.Equalf(t, "error", ev.Level, "level of plain error")
+	assert.Equalf(t, "500", ev.Tags["status"], "status of plain error")
+	assert.Equalf(t, "*errors.errorString", ev.Exception.Values[0].Type, "type of plain error")
+	assert.Nilf(t, ev.Exception.Values[0].Stacktrace, "plain error has no stack")
+}
+
+func TestNewEventMetaError(t *testing.T) {
+	assert.Nilf(t, sentryerrors.NewEvent(nil), "nil error")
+	exporter := &sentryerrors.Exporter{Release: "v1.0.0"}
+	assert.Nilf(t, exporter.Event(nil), "nil error")
+
+	ev := sentryerrors.NewEvent(errors.NotFound)
+	if assert.Lenf(t, ev.Exception.Values, 1, "empty cause skipped") {
+		assert.Equalf(t, "not_found(5)", ev.Exception.Values[0].Type, "meta error type")
+		assert.Equalf(t, "not found", ev.Exception.Values[0].Value, "meta error value")
+	}
+	ev = sentryerrors.NewEvent(errors.WithError(errors.NotFound, errors.Unavailable))
+	if assert.Lenf(t, ev.Exception.Values, 1, "empty cause skipped") {
+		assert.Equalf(t, "unavailable(14)", ev.Exception.Values[0].Type, "attached meta error type")
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	dsn, err := sentryerrors.ParseDSN("https://key@sentry.example.com/prefix/42")
+	assert.Nilf(t, err, "parse dsn")
+	assert.Equalf(t, "key", dsn.PublicKey, "public key")
+	assert.Equalf(t, "42", dsn.ProjectID, "project id")
+	assert.Equalf(t, "https://sentry.example.com/prefix/api/42/envelope/", dsn.Endpoint, "endpoint")
+	for _, bad := range []string{"ftp://key@host/1", "https://host/1", "https://key@host/", ":"} {
+		_, err = sentryerrors.ParseDSN(bad)
+		assert.Truef(t, errors.Is(err, errors.InvalidArgument), "%s: %v", bad, err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var (
+		path, auth, contentType string
+		lines                   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("X-Sentry-Auth")
+		contentType = r.Header.Get("Content-Type")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	}))
+	defer server.Close()
+
+	exporter, err := sentryerrors.NewExporter(strings.Replace(server.URL, "://", "://public@", 1) + "/7")
+	assert.Nilf(t, err, "new exporter")
+	exporter.Release = "v1.0.0"
+	exporter.Environment = "test"
+	id, err := exporter.Export(context.Background(), errors.WithError(io.EOF, errors.Unavailable))
+	assert.Nilf(t, err, "export")
+	assert.Lenf(t, id, 32, "event id")
+	assert.Equalf(t, "/api/7/envelope/", path, "path")
+	assert.Equalf(t, "Sentry sentry_version=7, sentry_client=ccmonky-errors/1.0, sentry_key=public", auth, "auth header")
+	assert.Equalf(t, "application/x-sentry-envelope", contentType, "content type")
+	if assert.Lenf(t, lines, 3, "envelope header, item header & event") {
+		var header, item map[string]any
+		var ev sentryerrors.Event
+		assert.Nilf(t, json.Unmarshal([]byte(lines[0]), &header), "envelope header")
+		assert.Equalf(t, id, header["event_id"], "envelope event id")
+		assert.Nilf(t, json.Unmarshal([]byte(lines[1]), &item), "item header")
+		assert.Equalf(t, "event", item["type"], "item type")
+		assert.EqualValuesf(t, len(lines[2]), item["length"], "item length")
+		assert.Nilf(t, json.Unmarshal([]byte(lines[2]), &ev), "event")
+		assert.Equalf(t, id, ev.EventID, "event id")
+		assert.Equalf(t, "v1.0.0", ev.Release, "release")
+		assert.Equalf(t, "test", ev.Environment, "environment")
+		assert.Equalf(t, "unavailable(14)", ev.Tags["meta.code"], "code tag")
+		assert.Equalf(t, "503", ev.Tags["status"], "status tag")
+	}
+
+	id, err = exporter.Export(context.Background(), nil)
+	assert.Nilf(t, err, "nil not exported")
+	assert.Equalf(t, "", id, "nil not exported")
+}
+
+func TestExportFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	exporter, err := sentryerrors.NewExporter(strings.Replace(server.URL, "://", "://public@", 1) + "/7")
+	assert.Nilf(t, err, "new exporter")
+	_, err = exporter.Export(context.Background(), io.EOF)
+	assert.Truef(t, errors.Is(err, errors.Unavailable), "export failed")
+	assert.Containsf(t, err.Error(), "429", "status of server")
+}
@@ -0,0 +1,148 @@
+package sentryerrors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ccmonky/errors"
+)
+
+// ClientName used in `X-Sentry-Auth` header
+var ClientName = "ccmonky-errors/1.0"
+
+// DSN is the parsed Sentry DSN, e.g. `https://<public_key>@<host>/<project_id>`
+type DSN struct {
+	raw       string
+	PublicKey string
+	ProjectID string
+	Endpoint  string // NOTE: envelope endpoint, e.g. `https://<host>/api/<project_id>/envelope/`
+}
+
+// ParseDSN parses Sentry DSN
+func ParseDSN(dsn string) (*DSN, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.WithError(err, errors.InvalidArgument)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.WithError(fmt.Errorf("unsupported dsn scheme %q", u.Scheme), errors.InvalidArgument)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, errors.WithError(fmt.Errorf("dsn missing public key"), errors.InvalidArgument)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 || path[i+1:] == "" {
+		return nil, errors.WithError(fmt.Errorf("dsn missing project id"), errors.InvalidArgument)
+	}
+	return &DSN{
+		raw:       dsn,
+		PublicKey: u.User.Username(),
+		ProjectID: path[i+1:],
+		Endpoint:  fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path[:i], path[i+1:]),
+	}, nil
+}
+
+// String returns the raw dsn
+func (d *DSN) String() string {
+	return d.raw
+}
+
+// Exporter sends errors as Sentry event envelopes
+type Exporter struct {
+	DSN         *DSN
+	Client      *http.Client
+	Release     string
+	Environment string
+	ServerName  string
+}
+
+// NewExporter creates a new Exporter with dsn, `http.DefaultClient` used if Client not specified
+func NewExporter(dsn string) (*Exporter, error) {
+	d, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		DSN:    d,
+		Client: http.DefaultClient,
+	}, nil
+}
+
+// Event creates Sentry event from err by `NewEvent` and fill exporter's release, environment and server name, returns
+// nil if err is nil
+func (e *Exporter) Event(err error) *Event {
+	ev := NewEvent(err)
+	if ev == nil {
+		return nil
+	}
+	ev.Release = e.Release
+	ev.Environment = e.Environment
+	ev.ServerName = e.ServerName
+	return ev
+}
+
+// Envelope encodes event as Sentry envelope: envelope header, item header and event payload separated by newlines
+func (e *Exporter) Envelope(ev *Event) ([]byte, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	header := map[string]any{
+		"event_id": ev.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if e.DSN != nil {
+		header["dsn"] = e.DSN.String()
+	}
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(map[string]any{"type": "event", "length": len(payload)}); err != nil {
+		return nil, err
+	}
+	buf.Write(payload)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Export sends err to Sentry and returns the event id, nil err will be ignored
+func (e *Exporter) Export(ctx context.Context, err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	ev := e.Event(err)
+	body, merr := e.Envelope(ev)
+	if merr != nil {
+		return "", merr
+	}
+	req, rerr := http.NewRequestWithContext(ctx, http.MethodPost, e.DSN.Endpoint, bytes.NewReader(body))
+	if rerr != nil {
+		return "", rerr
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", ClientName, e.DSN.PublicKey))
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, derr := client.Do(req)
+	if derr != nil {
+		return "", errors.WithError(derr, errors.Unavailable)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", errors.WithError(fmt.Errorf("sentry responds %s", resp.Status), errors.Unavailable)
+	}
+	return ev.EventID, nil
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the ordered level of error, usually used to distinguish user errors from system faults in alerting
+type Severity int
+
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// SeverityAttr used to attach severity on error, if no severity attached, `SeverityAttr.Get` returns the severity of the
+// latest meta if it's a builtin MetaError(e.g. `SeverityInfo` of `NotFound`), otherwise returns `SeverityError` if
+// err != nil, or `SeverityDebug` if err == nil
+var SeverityAttr = NewAttr[Severity]("severity",
+	WithAttrDefault(func(err error) any {
+		if err == nil {
+			return SeverityDebug
+		}
+		if metas := MetaAttr.GetAll(err); len(metas) > 0 {
+			if s, ok := builtinSeverity(metas[len(metas)-1]); ok {
+				return s
+			}
+		}
+		return SeverityError
+	}),
+	WithAttrDescription("severity level as an attr"))
+
+// helper functions for severity attr
+var (
+	WithSeverity   = SeverityAttr.With
+	SeverityOption = SeverityAttr.Option
+)
+
+// MaxSeverity returns the max severity of all severities in err chain(including severities of all attached builtin
+// MetaErrors), returns the default value of `SeverityAttr` if no severity found
+func MaxSeverity(err error) Severity {
+	all := SeverityAttr.GetAll(err)
+	for _, m := range MetaAttr.GetAll(err) {
+		if s, ok := builtinSeverity(m); ok {
+			all = append(all, s)
+		}
+	}
+	if len(all) == 0 {
+		return SeverityAttr.Get(err)
+	}
+	max := all[0]
+	for _, s := range all[1:] {
+		if s > max {
+			max = s
+		}
+	}
+	return max
+}
+
+// ParseSeverity parse severity from name
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if strings.EqualFold(n, name) {
+			return Severity(s), nil
+		}
+	}
+	return SeverityDebug, WithError(fmt.Errorf("invalid severity %q", name), InvalidArgument)
+}
+
+func (s Severity) String() string {
+	if s >= SeverityDebug && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (s *Severity) UnmarshalText(text []byte) error {
+	v, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+var severityNames = []string{"debug", "info", "warning", "error", "critical"}
@@ -0,0 +1,46 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity(t *testing.T) {
+	assert.Truef(t, errors.SeverityDebug < errors.SeverityInfo && errors.SeverityError < errors.SeverityCritical, "ordered")
+	assert.Equalf(t, errors.SeverityInfo, errors.SeverityAttr.Get(errors.InvalidArgument), "invalid argument")
+	assert.Equalf(t, errors.SeverityCritical, errors.SeverityAttr.Get(errors.DataLoss), "data loss")
+	assert.Equalf(t, errors.SeverityError, errors.SeverityAttr.Get(io.EOF), "default")
+	assert.Equalf(t, errors.SeverityDebug, errors.SeverityAttr.Get(nil), "nil")
+	for _, me := range []errors.MetaError{
+		errors.OK, errors.Canceled, errors.Unknown, errors.InvalidArgument, errors.DeadlineExceeded, errors.NotFound,
+		errors.AlreadyExists, errors.PermissionDenied, errors.ResourceExhausted, errors.FailedPrecondition,
+		errors.Aborted, errors.OutOfRange, errors.Unimplemented, errors.Internal, errors.Unavailable,
+		errors.DataLoss, errors.Unauthenticated,
+	} {
+		assert.Emptyf(t, errors.SeverityAttr.GetAll(me), "%s has no severity attached", me.Code())
+		assert.Equalf(t, errors.SeverityAttr.Get(me), errors.SeverityAttr.Get(errors.WithError(io.EOF, me)), "%s has builtin severity", me.Code())
+	}
+	assert.Equalf(t, errors.SeverityDebug, errors.SeverityAttr.Get(errors.OK), "ok")
+	assert.Equalf(t, errors.SeverityWarning, errors.SeverityAttr.Get(errors.WithError(io.EOF, errors.DeadlineExceeded)), "builtin severity of the latest meta")
+	assert.Equalf(t, errors.SeverityError, errors.SeverityAttr.Get(errors.WithError(io.EOF, errors.NewMetaError("x", "user(1)", "user"))), "not builtin")
+
+	err := errors.WithError(errors.WithError(io.EOF, errors.DataLoss), errors.NotFound)
+	assert.Equalf(t, errors.SeverityInfo, errors.SeverityAttr.Get(err), "latest severity")
+	assert.Equalf(t, errors.SeverityCritical, errors.MaxSeverity(err), "max severity")
+	assert.Equalf(t, errors.SeverityError, errors.MaxSeverity(io.EOF), "max severity default")
+	assert.Equalf(t, errors.SeverityWarning, errors.MaxSeverity(errors.WithSeverity(errors.WithError(io.EOF, errors.NotFound), errors.SeverityWarning)), "with severity")
+
+	data, jerr := json.Marshal(errors.SeverityWarning)
+	assert.Nilf(t, jerr, "marshal")
+	assert.Equalf(t, `"warning"`, string(data), "marshal")
+	var s errors.Severity
+	assert.Nilf(t, json.Unmarshal([]byte(`"Critical"`), &s), "unmarshal")
+	assert.Equalf(t, errors.SeverityCritical, s, "unmarshal")
+	_, perr := errors.ParseSeverity("fatal")
+	assert.Truef(t, errors.Is(perr, errors.InvalidArgument), "parse invalid")
+	assert.Equalf(t, "severity(9)", errors.Severity(9).String(), "unknown string")
+}
@@ -0,0 +1,61 @@
+//go:build go1.21
+// +build go1.21
+
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// LogValue implement slog.LogValuer, err will be logged as a group of `cause` and all flattened attrs(see `Map`),
+// context.Context is logged as the values extracted by registered context extractors(`ctx.{name}`)
+func (e *valueError) LogValue() slog.Value {
+	m := Map(e)
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		switch v.(type) {
+		case error, *Meta, context.Context, *stack: // NOTE: flattened or too verbose
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys)+1)
+	if cause := Cause(e); cause != nil && cause != empty {
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, m[k]))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Level returns the slog level of severity, `SeverityCritical` is mapped to `slog.LevelError+4`
+func (s Severity) Level() slog.Level {
+	switch {
+	case s <= SeverityDebug:
+		return slog.LevelDebug
+	case s == SeverityInfo:
+		return slog.LevelInfo
+	case s == SeverityWarning:
+		return slog.LevelWarn
+	case s == SeverityError:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// LogError logs err with key `err` at the level of `MaxSeverity(err)`, so that log levels follow error severities
+func LogError(ctx context.Context, logger *slog.Logger, msg string, err error, args ...any) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(ctx, MaxSeverity(err).Level(), msg, append([]any{"err", err}, args...)...)
+}
+
+var (
+	_ slog.LogValuer = (*valueError)(nil)
+)
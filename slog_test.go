@@ -0,0 +1,58 @@
+//go:build go1.21
+// +build go1.21
+
+package errors_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogValue(t *testing.T) {
+	errors.RegisterContextValue("request_id", ctxKey("request_id"))
+	defer errors.UnregisterContextExtractor("request_id")
+
+	ctx := context.WithValue(context.TODO(), ctxKey("request_id"), "r1")
+	err := errors.WithCtx(errors.WithMessage(errors.WithError(errors.New("xxx"), errors.NotFound), "wrapper"), ctx)
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	var record map[string]any
+	assert.Nilf(t, json.Unmarshal(buf.Bytes(), &record), "unmarshal record")
+	assert.Equalf(t, map[string]any{
+		"cause":          "xxx",
+		"ctx.request_id": "r1",
+		"meta.app":       "myapp",
+		"meta.code":      "not_found(5)",
+		"meta.message":   "not found",
+		"meta.source":    "github.com/ccmonky/errors",
+		"msg":            "wrapper",
+		"status":         float64(404),
+	}, record["err"], "err group")
+}
+
+func TestLogError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	for _, c := range []struct {
+		err   error
+		level string
+	}{
+		{errors.NotFound, "INFO"},
+		{errors.ResourceExhausted, "WARN"},
+		{errors.New("xxx"), "ERROR"},
+		{errors.WithError(errors.New("xxx"), errors.DataLoss), "ERROR+4"},
+	} {
+		buf.Reset()
+		errors.LogError(context.TODO(), logger, "failed", c.err, "k", "v")
+		var record map[string]any
+		assert.Nilf(t, json.Unmarshal(buf.Bytes(), &record), "unmarshal record")
+		assert.Equalf(t, c.level, record["level"], "level of %v", c.err)
+		assert.Equalf(t, "v", record["k"], "args")
+	}
+}
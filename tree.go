@@ -0,0 +1,127 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TreeOption tree renderer control option
+type TreeOption func(*treeOptions)
+
+type treeOptions struct {
+	color bool
+}
+
+// TreeColor render tree with ANSI colors, usually used in terminal
+func TreeColor() TreeOption {
+	return func(o *treeOptions) {
+		o.color = true
+	}
+}
+
+// FormatTree renders err as a tree: the cause is the root, each attr is a labeled child from the oldest to the latest,
+// nested error chains(e.g. attached by `WithError`) are indented with their own attrs, and stack frames are collapsed,
+// `%#v` of err(or `%+v` if `SetFormatMode(Tree)`) renders the same tree
+//
+// Usage:
+//
+//     fmt.Printf("%#v\n", errors.WithError(errors.New("xxx"), errors.NotFound))
+//     // *errors.fundamental("xxx")
+//     // └── error = not_found(5)
+//     //     ├── meta = myapp:github.com/ccmonky/errors:not_found(5):not found
+//     //     └── status = 404
+func FormatTree(err error, opts ...TreeOption) string {
+	var o treeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var nodes []Node // NOTE: walk order, i.e. from the latest to the oldest, nested nodes follow their parent
+	walk(err, 0, nil, func(n Node) WalkAction {
+		nodes = append(nodes, n)
+		return WalkContinue
+	})
+	var sb strings.Builder
+	sb.WriteString(o.paint(treeRootLabel(err), ansiBold))
+	sb.WriteByte('\n')
+	o.writeTree(&sb, nodes, "")
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (o *treeOptions) writeTree(sb *strings.Builder, nodes []Node, indent string) {
+	end := len(nodes)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		n := nodes[i]
+		if n.Depth != nodes[0].Depth {
+			continue
+		}
+		branch, next := "├── ", "│   "
+		if i == 0 {
+			branch, next = "└── ", "    "
+		}
+		sb.WriteString(o.paint(indent+branch, ansiDim) + o.paint(treeKey(n.Key), ansiCyan) + " = ")
+		if isChain(n.Value) {
+			sb.WriteString(o.paint(treeRootLabel(n.Value.(error)), ansiBold))
+			sb.WriteByte('\n')
+			o.writeTree(sb, nodes[i+1:end], indent+next)
+		} else {
+			sb.WriteString(treeValue(n.Value))
+			sb.WriteByte('\n')
+		}
+		end = i
+	}
+}
+
+// treeRootLabel returns the label of error chain: the base of the chain(see `chainBase`, foreign wrappers are kept), or
+// the code of meta if the base is empty
+func treeRootLabel(err error) string {
+	base := chainBase(err)
+	switch {
+	case err == nil:
+		return "<nil>"
+	case base == empty:
+		if m := MetaAttr.Get(err); m != nil {
+			return m.code
+		}
+		return "<empty>"
+	}
+	return fmt.Sprintf("%T(%q)", base, base.Error())
+}
+
+func treeKey(key any) string {
+	if sp, ok := key.(*string); ok {
+		return *sp
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+func treeValue(v any) string {
+	switch tv := v.(type) {
+	case *stack:
+		st := tv.StackTrace()
+		if len(st) == 0 {
+			return "[]"
+		}
+		return fmt.Sprintf("%n(%v) +%d frames", st[0], st[0], len(st)-1)
+	case *Meta:
+		return fmt.Sprintf("%+v", tv)
+	case context.Context:
+		return fmt.Sprintf("%v", ExtractContext(tv))
+	case error:
+		return fmt.Sprintf("%T(%q)", tv, tv.Error())
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+const (
+	ansiBold = "\x1b[1m"
+	ansiDim  = "\x1b[2m"
+	ansiCyan = "\x1b[36m"
+)
+
+func (o *treeOptions) paint(s, color string) string {
+	if !o.color || s == "" {
+		return s
+	}
+	return color + s + "\x1b[0m"
+}
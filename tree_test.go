@@ -0,0 +1,63 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTree(t *testing.T) {
+	err := errors.WithError(errors.WithStack(errors.New("xxx")), errors.NotFound)
+	err = errors.WithError(err, errors.WithError(errors.New("nested"), errors.Unavailable))
+	err = errors.WithMessage(err, "msg")
+	want := `*errors.fundamental("xxx")
+├── stack = TestFormatTree(tree_test.go:13) +2 frames
+├── error = not_found(5)
+│   ├── meta = myapp:github.com/ccmonky/errors:not_found(5):not found
+│   └── status = 404
+├── error = *errors.fundamental("nested")
+│   └── error = unavailable(14)
+│       ├── meta = myapp:github.com/ccmonky/errors:unavailable(14):service is unavailable
+│       └── status = 503
+└── msg = msg`
+	assert.Equal(t, want, errors.FormatTree(err))
+	assert.Equalf(t, want, fmt.Sprintf("%#v", err), "%%#v")
+	assert.Equal(t, "<nil>", errors.FormatTree(nil))
+	assert.Equal(t, `*errors.fundamental("xxx")`, errors.FormatTree(errors.New("xxx")))
+
+	colored := errors.FormatTree(err, errors.TreeColor())
+	assert.Contains(t, colored, "\x1b[36mstatus\x1b[0m = 404")
+	assert.Equalf(t, want, stripANSI(colored), "same tree without colors")
+
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "├── ")
+	errors.SetFormatMode(errors.Tree)
+	defer errors.SetFormatMode(errors.Default)
+	assert.Equalf(t, want, fmt.Sprintf("%+v", err), "tree mode")
+	assert.Equalf(t, "xxx:stack={}", errors.WithStack(errors.New("xxx")).Error()[:len("xxx:stack={}")], "error string not changed")
+}
+
+func stripANSI(s string) string {
+	for _, code := range []string{"\x1b[0m", "\x1b[1m", "\x1b[2m", "\x1b[36m"} {
+		s = strings.ReplaceAll(s, code, "")
+	}
+	return s
+}
+
+func TestFormatTreeForeignWrapper(t *testing.T) {
+	inner := errors.WithError(errors.New("xxx"), errors.NotFound)
+	wrapped := fmt.Errorf("wrap: %w", inner)
+	assert.Equalf(t, fmt.Sprintf("%T(%q)", wrapped, wrapped.Error()), errors.FormatTree(wrapped), "wrapper is the root")
+	assert.Containsf(t, errors.FormatTree(wrapped), "wrap: xxx:error={meta={source=errors;code=not_found(5)}", "text of the wrapper and the chain kept")
+
+	err := errors.WithError(wrapped, errors.Unavailable)
+	err = errors.WithError(errors.New("outer"), err)
+	want := fmt.Sprintf(`*errors.fundamental("outer")
+└── error = %T(%q)
+    └── error = unavailable(14)
+        ├── meta = myapp:github.com/ccmonky/errors:unavailable(14):service is unavailable
+        └── status = 503`, wrapped, wrapped.Error())
+	assert.Equalf(t, want, fmt.Sprintf("%#v", err), "nested chain ends at the wrapper")
+}
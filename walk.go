@@ -0,0 +1,111 @@
+package errors
+
+// WalkAction controls the traversal of `Walk`
+type WalkAction int
+
+const (
+	// WalkContinue continue the traversal, descend into the node value if it's an error chain
+	WalkContinue WalkAction = iota
+
+	// WalkSkipChildren continue the traversal, but do not descend into the node value
+	WalkSkipChildren
+
+	// WalkStop stop the traversal
+	WalkStop
+)
+
+// Node is a key-value pair of the error chain visited by `Walk`
+type Node struct {
+	// Key is the key of the node, usually the key of `Attr`
+	Key any
+
+	// Value is the value of the node
+	Value any
+
+	// Depth is 0 for the nodes of err itself, and increased by 1 for the nodes of each nested error chain value
+	Depth int
+
+	// Path is the keys of the ancestor nodes from the outermost to the innermost, only valid during the visit, copy it if
+	// you want to retain it
+	Path []any
+}
+
+// unwrapAller is the interface of an error chain node
+type unwrapAller interface {
+	UnwrapAll() (any, any, error)
+}
+
+// Walk visits all nodes of err in depth-first order: nodes of the chain are visited from the latest to the oldest, and
+// if the value of a node is also an error chain(e.g. attached by `WithError`), its nodes are visited right after it,
+// the cause is not visited as a node, use `Cause` to get it
+//
+// Usage:
+//
+//     errors.Walk(err, func(n errors.Node) errors.WalkAction {
+//         fmt.Println(strings.Repeat("  ", n.Depth), n.Key, n.Value)
+//         return errors.WalkContinue
+//     })
+func Walk(err error, fn func(Node) WalkAction) {
+	path := make([]any, 0, 4)
+	walk(err, 0, &path, fn)
+}
+
+// walk returns true if stopped, path is nil means path not tracked, which keeps lookups allocation free
+func walk(err error, depth int, path *[]any, fn func(Node) WalkAction) bool {
+	for err != nil {
+		uv, ok := err.(unwrapAller)
+		if !ok {
+			return false
+		}
+		k, v, next := uv.UnwrapAll()
+		if _, ok := k.(contextOptionsAppliedKey); ok { // NOTE: invisible marker of `FromContext`
+			err = next
+			continue
+		}
+		n := Node{Key: k, Value: v, Depth: depth}
+		if path != nil {
+			n.Path = *path
+		}
+		switch fn(n) {
+		case WalkStop:
+			return true
+		case WalkContinue:
+			if isChain(v) {
+				if path != nil {
+					*path = append(*path, k)
+				}
+				stopped := walk(v.(error), depth+1, path, fn)
+				if path != nil {
+					*path = (*path)[:len(*path)-1]
+				}
+				if stopped {
+					return true
+				}
+			}
+		}
+		err = next
+	}
+	return false
+}
+
+// chainBase returns the error where the chain ends, i.e. the first error which is not a node of the chain, it's where
+// `walk` stops, unlike `Cause` it does not unwrap foreign wrappers(e.g. `fmt.Errorf` with `%w`)
+func chainBase(err error) error {
+	for err != nil {
+		uv, ok := err.(unwrapAller)
+		if !ok {
+			break
+		}
+		_, _, err = uv.UnwrapAll()
+	}
+	return err
+}
+
+// isChain reports whether v is an error chain which will be visited as nested nodes by `Walk`
+func isChain(v any) bool {
+	if ve, ok := v.(error); ok {
+		_, ok = ve.(unwrapAller)
+		return ok
+	}
+	return false
+}
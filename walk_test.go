@@ -0,0 +1,76 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ccmonky/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	inner := errors.WithError(errors.New("inner"), errors.NotFound)
+	err := errors.WithError(errors.New("xxx"), inner)
+	err = errors.WithMessage(err, "msg")
+
+	var visited []string
+	errors.Walk(err, func(n errors.Node) errors.WalkAction {
+		var path []string
+		for _, k := range n.Path {
+			path = append(path, fmt.Sprint(*k.(*string)))
+		}
+		visited = append(visited, fmt.Sprintf("%d:%v:%s", n.Depth, path, *n.Key.(*string)))
+		return errors.WalkContinue
+	})
+	assert.Equal(t, []string{
+		"0:[]:msg",
+		"0:[]:error",
+		"1:[error]:error",
+		"2:[error error]:status",
+		"2:[error error]:meta",
+	}, visited)
+
+	visited = nil
+	errors.Walk(err, func(n errors.Node) errors.WalkAction {
+		visited = append(visited, *n.Key.(*string))
+		return errors.WalkSkipChildren
+	})
+	assert.Equalf(t, []string{"msg", "error"}, visited, "skip children")
+
+	visited = nil
+	errors.Walk(err, func(n errors.Node) errors.WalkAction {
+		visited = append(visited, *n.Key.(*string))
+		if n.Key == errors.StatusAttr.Key() {
+			return errors.WalkStop
+		}
+		return errors.WalkContinue
+	})
+	assert.Equalf(t, []string{"msg", "error", "error", "status"}, visited, "stop")
+
+	errors.Walk(errors.New("xxx"), func(n errors.Node) errors.WalkAction {
+		t.Fatal("cause should not be visited")
+		return errors.WalkContinue
+	})
+	errors.Walk(nil, func(n errors.Node) errors.WalkAction {
+		t.Fatal("nil should not be visited")
+		return errors.WalkContinue
+	})
+}
+
+func TestWalkHelpers(t *testing.T) {
+	a := errors.WithError(errors.New("a"), errors.NotFound)
+	x := errors.WithError(errors.WithError(errors.New("x"), a), errors.Unavailable)
+	err := errors.WithError(errors.New("cause"), x)
+
+	assert.Equalf(t, []error{errors.Cause(err), errors.NotFound, a, errors.Unavailable, x}, errors.GetAllErrors(err), "nested errors from the oldest to the latest")
+	assert.Equal(t, 503, errors.StatusAttr.Get(err))
+	assert.Equal(t, []int{404, 503}, errors.StatusAttr.GetAll(err))
+	assert.Equal(t, "unavailable(14)", errors.Map(err)["meta.code"])
+	assert.Len(t, errors.Options(err), 1)
+}
+
+func TestWalkAllocs(t *testing.T) {
+	err := errors.WithMessage(errors.WithError(errors.WithError(errors.New("x"), errors.NotFound), errors.Unavailable), "m")
+	assert.Equalf(t, 0.0, testing.AllocsPerRun(100, func() { errors.StatusAttr.Get(err) }), "get hit")
+	assert.Equalf(t, 0.0, testing.AllocsPerRun(100, func() { errors.PanicAttr.Get(err) }), "get miss")
+}